@@ -0,0 +1,40 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClientEndToEnd confere o SDK contra as respostas reais do Service A
+func TestClientEndToEnd(t *testing.T) {
+	h := Start(t)
+	c := client.New(client.WithBaseURL(h.ServiceAURL))
+
+	weather, err := c.GetWeather(context.Background(), "26140040")
+	require.NoError(t, err)
+	assert.Equal(t, &client.WeatherResponse{City: "Belford Roxo", TempC: float(31.2), TempF: float(88.2), TempK: float(304.2)}, weather)
+
+	precision := 2
+	weather, err = c.GetWeatherWithOptions(context.Background(), client.WeatherRequest{
+		CEP:              "26140040",
+		Units:            []string{"K", "R"},
+		Precision:        &precision,
+		ScientificKelvin: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &client.WeatherResponse{City: "Belford Roxo", TempK: float(304.35), TempR: float(547.83)}, weather)
+
+	_, err = c.GetWeather(context.Background(), "99999999")
+	assert.ErrorIs(t, err, client.ErrNotFound)
+
+	_, err = c.GetWeather(context.Background(), "1234567a")
+	assert.ErrorIs(t, err, client.ErrInvalidZipcode)
+}
+
+func float(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,45 @@
+package e2e
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestDeadlineBudgetEndToEnd(t *testing.T) {
+	// A WeatherAPI demora mais do que todo o prazo da requisição
+	h := Start(t,
+		WithServiceAConfig("request_timeout", "500ms"),
+		WithServiceBConfig("faults", `{"weatherapi":{"latency":"5s"}}`),
+	)
+
+	started := time.Now()
+	resp, err := http.Post(h.ServiceAURL+"/weather", "application/json", strings.NewReader(`{"cep":"26140040"}`))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	assert.JSONEq(t, `{"message":"deadline exceeded"}`, string(body))
+	assert.Less(t, time.Since(started), 2*time.Second, "the budget, not the 9s default, bounds the request")
+
+	// O Service B recebeu o restante do prazo do Service A, não o próprio REQUEST_TIMEOUT
+	var budgets []int64
+	for _, span := range h.Spans(t, 9) {
+		for _, attr := range span.Attributes {
+			if attr.Key == attribute.Key("request.timeout_ms") {
+				budgets = append(budgets, attr.Value.AsInt64())
+			}
+		}
+	}
+	require.Len(t, budgets, 2, "service A and service B record their budgets")
+	for _, budget := range budgets {
+		assert.LessOrEqual(t, budget, int64(500))
+	}
+}
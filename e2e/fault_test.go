@@ -0,0 +1,47 @@
+package e2e
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestFaultInjectionEndToEnd(t *testing.T) {
+	h := Start(t, WithServiceBConfig("faults", `{"weatherapi":{"error_rate":1,"status":503}}`))
+
+	resp, err := http.Post(h.ServiceAURL+"/weather", "application/json", strings.NewReader(`{"cep":"26140040"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	// Sem span do cliente de teste, cada serviço inicia seu trecho a partir da requisição
+	spans := h.Spans(t, 9)
+	injected := faultSpans(spans)
+	require.Len(t, injected, 1)
+	assert.Equal(t, "HTTP GET", injected[0].Name)
+	require.Len(t, injected[0].Events, 1)
+	assert.Equal(t, "fault.injected", injected[0].Events[0].Name)
+	assert.Contains(t, injected[0].Events[0].Attributes, attribute.String("fault.dependency", "weatherapi"))
+	assert.Contains(t, injected[0].Events[0].Attributes, attribute.Int("fault.status", http.StatusServiceUnavailable))
+
+	// A falha injetada não interrompe a propagação entre os serviços
+	assert.Len(t, TraceIDs(spans), 1)
+}
+
+// faultSpans filtra os spans marcados com fault.injected=true
+func faultSpans(spans tracetest.SpanStubs) tracetest.SpanStubs {
+	var marked tracetest.SpanStubs
+	for _, span := range spans {
+		for _, attr := range span.Attributes {
+			if attr == attribute.Bool("fault.injected", true) {
+				marked = append(marked, span)
+			}
+		}
+	}
+	return marked
+}
@@ -0,0 +1,193 @@
+// Package e2e sobe o Service A e o Service B no mesmo processo, em portas
+// aleatórias, contra o ViaCEP e a WeatherAPI emulados (pkg/stubs) e com os
+// spans de ambos coletados em memória.
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/stubs"
+	serviceA "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/app"
+	serviceB "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/app"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option customiza a stack antes de subir
+type Option func(*options)
+
+type options struct {
+	stubs    []stubs.Option
+	serviceA map[string]interface{}
+	serviceB map[string]interface{}
+}
+
+// WithStubs configura o ViaCEP e a WeatherAPI emulados (latência, erros, CEPs desconhecidos)
+func WithStubs(opts ...stubs.Option) Option {
+	return func(o *options) {
+		o.stubs = append(o.stubs, opts...)
+	}
+}
+
+// WithServiceAConfig sobrescreve uma chave de configuração do Service A (ex: "rate_limit_global")
+func WithServiceAConfig(key string, value interface{}) Option {
+	return func(o *options) {
+		o.serviceA[key] = value
+	}
+}
+
+// WithServiceBConfig sobrescreve uma chave de configuração do Service B
+func WithServiceBConfig(key string, value interface{}) Option {
+	return func(o *options) {
+		o.serviceB[key] = value
+	}
+}
+
+// Harness é a stack em execução; tudo é encerrado no fim do teste
+type Harness struct {
+	ServiceAURL string
+	ServiceBURL string
+	UpstreamURL string
+
+	spans *tracetest.InMemoryExporter
+}
+
+// Start sobe stubs, Service B e Service A, nessa ordem. O TracerProvider e os
+// propagadores globais são substituídos, então testes com Harness não podem
+// rodar em paralelo.
+func Start(t *testing.T, opts ...Option) *Harness {
+	t.Helper()
+
+	o := &options{serviceA: make(map[string]interface{}), serviceB: make(map[string]interface{})}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// Os handlers capturam o provider e os propagadores ao serem criados
+	spans := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	fixtures, err := stubs.LoadFixturesFile("")
+	if err != nil {
+		t.Fatalf("loading fixtures: %v", err)
+	}
+	upstream := httptest.NewServer(stubs.New(fixtures, o.stubs...).Handler())
+	t.Cleanup(upstream.Close)
+
+	configB := serviceB.NewConfig()
+	configB.Set("viacep_base_url", upstream.URL+"/ws")
+	configB.Set("weather_api_base_url", upstream.URL+"/v1")
+	configB.Set("weather_api_key", "e2e")
+	configB.Set("openapi_validate_responses", true)
+	apply(configB, o.serviceB)
+	cfgB, err := serviceB.LoadConfig(configB)
+	if err != nil {
+		t.Fatalf("service B: %v", err)
+	}
+	b := httptest.NewServer(serviceB.New(ctx, cfgB).Handler)
+	t.Cleanup(b.Close)
+
+	configA := serviceA.NewConfig()
+	configA.Set("service_b_url", b.URL)
+	configA.Set("service_b_transport", "http")
+	configA.Set("openapi_validate_responses", true)
+	apply(configA, o.serviceA)
+	cfgA, err := serviceA.LoadConfig(configA)
+	if err != nil {
+		t.Fatalf("service A: %v", err)
+	}
+	appA := serviceA.New(ctx, cfgA)
+	t.Cleanup(appA.Close)
+	a := httptest.NewServer(appA.Handler)
+	t.Cleanup(a.Close)
+
+	return &Harness{ServiceAURL: a.URL, ServiceBURL: b.URL, UpstreamURL: upstream.URL, spans: spans}
+}
+
+func apply(config *viper.Viper, values map[string]interface{}) {
+	for key, value := range values {
+		config.Set(key, value)
+	}
+}
+
+// Spans espera até que os n spans esperados terminem e os devolve em ordem de
+// início. Os spans dos servidores terminam depois que a resposta é enviada.
+func (h *Harness) Spans(t *testing.T, n int) tracetest.SpanStubs {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		spans := h.spans.GetSpans()
+		if len(spans) >= n {
+			// Aguarda um pouco para detectar spans além dos esperados
+			time.Sleep(50 * time.Millisecond)
+			spans = h.spans.GetSpans()
+			sort.SliceStable(spans, func(i, j int) bool { return spans[i].StartTime.Before(spans[j].StartTime) })
+			return spans
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d spans, got %d: %v", n, len(spans), SpanTree(spans))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// ResetSpans descarta os spans coletados até agora
+func (h *Harness) ResetSpans() {
+	h.spans.Reset()
+}
+
+// SpanTree descreve cada span como "nome <- pai : status", com "root" para o
+// span sem pai e "?" para um pai que não está entre os spans
+func SpanTree(spans tracetest.SpanStubs) []string {
+	names := make(map[trace.SpanID]string, len(spans))
+	for _, s := range spans {
+		names[s.SpanContext.SpanID()] = s.Name
+	}
+
+	tree := make([]string, 0, len(spans))
+	for _, s := range spans {
+		parent := "root"
+		if s.Parent.IsValid() {
+			var ok bool
+			if parent, ok = names[s.Parent.SpanID()]; !ok {
+				parent = "?"
+			}
+		}
+		tree = append(tree, fmt.Sprintf("%s <- %s : %s", s.Name, parent, s.Status.Code))
+	}
+	return tree
+}
+
+// TraceIDs lista os trace IDs distintos dos spans
+func TraceIDs(spans tracetest.SpanStubs) []trace.TraceID {
+	seen := make(map[trace.TraceID]bool)
+	var ids []trace.TraceID
+	for _, s := range spans {
+		if id := s.SpanContext.TraceID(); !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,137 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/stubs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestWeatherEndToEnd(t *testing.T) {
+	unset, errored := codes.Unset.String(), codes.Error.String()
+
+	tests := []struct {
+		name           string
+		opts           []Option
+		body           string
+		expectedStatus int
+		expectedBody   string
+		expectedSpans  []string
+	}{
+		{
+			name:           "success",
+			body:           `{"cep":"26140040"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"city":"Belford Roxo","temp_C":31.2,"temp_F":88.2,"temp_K":304.2}`,
+			expectedSpans: []string{
+				"e2e.client <- root : " + unset,
+				"service-a.handle-request <- e2e.client : " + unset,
+				"service-a.validate-input <- service-a.handle-request : " + unset,
+				"service-a.call-service-b <- service-a.handle-request : " + unset,
+				"HTTP POST <- service-a.call-service-b : " + unset,
+				"service-b.process-weather <- HTTP POST : " + unset,
+				"service-b.fetch-zipcode <- service-b.process-weather : " + unset,
+				"HTTP GET <- service-b.fetch-zipcode : " + unset,
+				"service-b.fetch-weather <- service-b.process-weather : " + unset,
+				"HTTP GET <- service-b.fetch-weather : " + unset,
+			},
+		},
+		{
+			name:           "invalid zipcode stops at service-a",
+			body:           `{"cep":"1234567a"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"message":"invalid zipcode"}`,
+			expectedSpans: []string{
+				"e2e.client <- root : " + unset,
+				"service-a.handle-request <- e2e.client : " + unset,
+				"service-a.validate-input <- service-a.handle-request : " + errored,
+			},
+		},
+		{
+			name:           "zipcode not found",
+			body:           `{"cep":"99999999"}`,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"message":"can not find zipcode"}`,
+			expectedSpans: []string{
+				"e2e.client <- root : " + unset,
+				"service-a.handle-request <- e2e.client : " + unset,
+				"service-a.validate-input <- service-a.handle-request : " + unset,
+				"service-a.call-service-b <- service-a.handle-request : " + errored,
+				"HTTP POST <- service-a.call-service-b : " + errored,
+				"service-b.process-weather <- HTTP POST : " + unset,
+				"service-b.fetch-zipcode <- service-b.process-weather : " + errored,
+				"HTTP GET <- service-b.fetch-zipcode : " + unset,
+			},
+		},
+		{
+			name:           "weather provider failure",
+			opts:           []Option{WithStubs(stubs.WithWeatherFaults(stubs.Faults{ErrorRate: 1, ErrorStatus: http.StatusServiceUnavailable}))},
+			body:           `{"cep":"26140040"}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"message":"internal server error"}`,
+			expectedSpans: []string{
+				"e2e.client <- root : " + unset,
+				"service-a.handle-request <- e2e.client : " + errored,
+				"service-a.validate-input <- service-a.handle-request : " + unset,
+				"service-a.call-service-b <- service-a.handle-request : " + errored,
+				"HTTP POST <- service-a.call-service-b : " + errored,
+				"service-b.process-weather <- HTTP POST : " + errored,
+				"service-b.fetch-zipcode <- service-b.process-weather : " + unset,
+				"HTTP GET <- service-b.fetch-zipcode : " + unset,
+				"service-b.fetch-weather <- service-b.process-weather : " + errored,
+				"HTTP GET <- service-b.fetch-weather : " + errored,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := Start(t, tt.opts...)
+
+			// O cliente inicia o trace e o propaga, como um chamador instrumentado
+			ctx, span := otel.Tracer("e2e").Start(context.Background(), "e2e.client")
+			req, err := http.NewRequestWithContext(ctx, "POST", h.ServiceAURL+"/weather", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			span.End()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assertJSONSubset(t, tt.expectedBody, string(body))
+
+			spans := h.Spans(t, len(tt.expectedSpans))
+			assert.Equal(t, tt.expectedSpans, SpanTree(spans))
+
+			// Um único trace, iniciado pelo cliente e atravessando os dois serviços
+			traceIDs := TraceIDs(spans)
+			require.Len(t, traceIDs, 1)
+			assert.Equal(t, span.SpanContext().TraceID(), traceIDs[0])
+		})
+	}
+}
+
+// assertJSONSubset confere os campos esperados, ignorando extras (ex: errors)
+func assertJSONSubset(t *testing.T, expected, actual string) {
+	t.Helper()
+
+	var want, got map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(expected), &want))
+	require.NoError(t, json.Unmarshal([]byte(actual), &got), actual)
+	for key, value := range want {
+		assert.Equal(t, value, got[key], key)
+	}
+}
@@ -0,0 +1,234 @@
+// Package client é o SDK Go da API pública do Service A (POST /weather), para
+// serviços que consultam o gateway sem reimplementar o cliente HTTP:
+//
+//	c := client.New(client.WithBaseURL("http://service-a:8080"), client.WithAPIKey(key), client.WithRetries(2, 0))
+//	weather, err := c.GetWeather(ctx, "26140040")
+//	weather, err = c.GetWeatherWithOptions(ctx, client.WeatherRequest{CEP: "26140040", Units: []string{"K", "R"}})
+//	if errors.Is(err, client.ErrNotFound) { ... }
+//
+// As chamadas são instrumentadas com otelhttp: o contexto de trace de ctx é
+// propagado e o Service A continua o trace do chamador.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// maxBackoff limita o crescimento exponencial da espera entre tentativas
+const maxBackoff = 2 * time.Second
+
+// WeatherRequest é o body de POST /weather
+type WeatherRequest struct {
+	CEP string `json:"cep"`
+	// Units são as escalas da resposta (C, F, K e R), na ordem desejada; vazio = C, F e K
+	Units []string `json:"units,omitempty"`
+	// Precision é o número de casas decimais (0 a 6); nil = 1
+	Precision *int `json:"precision,omitempty"`
+	// ScientificKelvin usa K = C + 273.15 em vez de C + 273
+	ScientificKelvin bool `json:"scientific_kelvin,omitempty"`
+}
+
+// WeatherResponse é a temperatura atual da cidade do CEP; as escalas não
+// pedidas em WeatherRequest.Units ficam nil
+type WeatherResponse struct {
+	City  string   `json:"city"`
+	TempC *float64 `json:"temp_C,omitempty"`
+	TempF *float64 `json:"temp_F,omitempty"`
+	TempK *float64 `json:"temp_K,omitempty"`
+	TempR *float64 `json:"temp_R,omitempty"`
+}
+
+// Client chama o Service A; é seguro para uso concorrente
+type Client struct {
+	baseURL      string
+	timeout      time.Duration
+	apiKey       string
+	bearerToken  string
+	userAgent    string
+	retries      int
+	backoff      time.Duration
+	maxRetryWait time.Duration
+	transport    http.RoundTripper
+	otelOpts     []otelhttp.Option
+	httpClient   *http.Client
+	// sleep espera entre tentativas; substituído nos testes
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func New(opts ...Option) *Client {
+	c := &Client{
+		baseURL:      DefaultBaseURL,
+		timeout:      10 * time.Second,
+		backoff:      100 * time.Millisecond,
+		maxRetryWait: 10 * time.Second,
+		transport:    http.DefaultTransport,
+		sleep:        sleep,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.httpClient = &http.Client{
+		Transport: otelhttp.NewTransport(c.transport, c.otelOpts...),
+		Timeout:   c.timeout,
+	}
+	return c
+}
+
+// GetWeather busca a temperatura atual da cidade do CEP (8 dígitos) em C, F e K.
+// Respostas de erro são devolvidas como *Error, comparáveis com os sentinels (ErrNotFound...).
+func (c *Client) GetWeather(ctx context.Context, cep string) (*WeatherResponse, error) {
+	return c.GetWeatherWithOptions(ctx, WeatherRequest{CEP: cep})
+}
+
+// GetWeatherWithOptions é GetWeather com as escalas, a precisão e a conversão
+// de Kelvin escolhidas em req
+func (c *Client) GetWeatherWithOptions(ctx context.Context, req WeatherRequest) (*WeatherResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		weather, err := c.getWeather(ctx, body)
+		wait, retry := c.retryWait(ctx, attempt, err)
+		if !retry {
+			return weather, err
+		}
+		if err := c.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) getWeather(ctx context.Context, body []byte) (*WeatherResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/weather", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling service A: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newError(resp, data)
+	}
+
+	var weather WeatherResponse
+	if err := json.Unmarshal(data, &weather); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+	return &weather, nil
+}
+
+// newError lê {"message", "errors"} (também presentes em application/problem+json)
+// ou, fora desse formato, usa o body como mensagem
+func newError(resp *http.Response, data []byte) *Error {
+	apiErr := &Error{StatusCode: resp.StatusCode, RetryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+
+	var errResp struct {
+		Message string       `json:"message"`
+		Errors  []FieldError `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &errResp); err != nil || errResp.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(data))
+		if apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return apiErr
+	}
+	apiErr.Message = errResp.Message
+	apiErr.Fields = errResp.Errors
+	return apiErr
+}
+
+// retryWait decide se a chamada é repetida e quanto esperar antes
+func (c *Client) retryWait(ctx context.Context, attempt int, err error) (time.Duration, bool) {
+	if err == nil || attempt >= c.retries || ctx.Err() != nil {
+		return 0, false
+	}
+
+	wait := c.backoff << attempt
+	if wait > maxBackoff || wait <= 0 {
+		wait = maxBackoff
+	}
+
+	// Falhas de conexão e timeouts da tentativa (*url.Error) são sempre repetidos
+	var apiErr *Error
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &apiErr):
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		default:
+			return 0, false
+		}
+		if apiErr.RetryAfter > wait {
+			wait = apiErr.RetryAfter
+		}
+	case !errors.As(err, &urlErr):
+		return 0, false
+	}
+
+	if wait > c.maxRetryWait {
+		return 0, false
+	}
+	return wait, true
+}
+
+// retryAfter aceita segundos ou data HTTP
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,309 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestClientGetWeather(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           int
+		header           http.Header
+		body             string
+		expectedResponse *WeatherResponse
+		expectedErr      *Error
+		expectedIs       error
+	}{
+		{
+			name:             "success",
+			status:           http.StatusOK,
+			body:             `{"city":"Belford Roxo","temp_C":31.2,"temp_F":88.2,"temp_K":304.2}`,
+			expectedResponse: &WeatherResponse{City: "Belford Roxo", TempC: float(31.2), TempF: float(88.2), TempK: float(304.2)},
+		},
+		{
+			name:        "invalid zipcode",
+			status:      http.StatusUnprocessableEntity,
+			body:        `{"message":"invalid zipcode","errors":[{"field":"cep","message":"must have 8 characters"}]}`,
+			expectedErr: &Error{StatusCode: http.StatusUnprocessableEntity, Message: "invalid zipcode", Fields: []FieldError{{Field: "cep", Message: "must have 8 characters"}}},
+			expectedIs:  ErrInvalidZipcode,
+		},
+		{
+			name:        "zipcode not found",
+			status:      http.StatusNotFound,
+			body:        `{"message":"can not find zipcode"}`,
+			expectedErr: &Error{StatusCode: http.StatusNotFound, Message: "can not find zipcode"},
+			expectedIs:  ErrNotFound,
+		},
+		{
+			name:        "unauthorized",
+			status:      http.StatusUnauthorized,
+			body:        `{"message":"unauthorized"}`,
+			expectedErr: &Error{StatusCode: http.StatusUnauthorized, Message: "unauthorized"},
+			expectedIs:  ErrUnauthorized,
+		},
+		{
+			name:        "forbidden",
+			status:      http.StatusForbidden,
+			body:        `{"message":"forbidden"}`,
+			expectedErr: &Error{StatusCode: http.StatusForbidden, Message: "forbidden"},
+			expectedIs:  ErrForbidden,
+		},
+		{
+			name:        "rate limited problem json",
+			status:      http.StatusTooManyRequests,
+			header:      http.Header{"Retry-After": {"3"}, "Content-Type": {"application/problem+json"}},
+			body:        `{"type":"about:blank","title":"Too Many Requests","status":429,"detail":"rate limit exceeded","message":"rate limit exceeded"}`,
+			expectedErr: &Error{StatusCode: http.StatusTooManyRequests, Message: "rate limit exceeded", RetryAfter: 3 * time.Second},
+			expectedIs:  ErrRateLimited,
+		},
+		{
+			name:        "unsupported media type",
+			status:      http.StatusUnsupportedMediaType,
+			body:        `{"message":"unsupported media type"}`,
+			expectedErr: &Error{StatusCode: http.StatusUnsupportedMediaType, Message: "unsupported media type"},
+			expectedIs:  ErrBadRequest,
+		},
+		{
+			name:        "non json server error",
+			status:      http.StatusBadGateway,
+			body:        "bad gateway\n",
+			expectedErr: &Error{StatusCode: http.StatusBadGateway, Message: "bad gateway"},
+			expectedIs:  ErrServer,
+		},
+		{
+			name:        "empty body",
+			status:      http.StatusInternalServerError,
+			expectedErr: &Error{StatusCode: http.StatusInternalServerError, Message: "Internal Server Error"},
+			expectedIs:  ErrServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "POST", r.Method)
+				assert.Equal(t, "/weather", r.URL.Path)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				body, _ := io.ReadAll(r.Body)
+				assert.JSONEq(t, `{"cep":"26140040"}`, string(body))
+
+				for name, values := range tt.header {
+					w.Header()[name] = values
+				}
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			resp, err := New(WithBaseURL(server.URL+"/")).GetWeather(context.Background(), "26140040")
+			if tt.expectedErr != nil {
+				var apiErr *Error
+				require.True(t, errors.As(err, &apiErr), err)
+				assert.Equal(t, tt.expectedErr, apiErr)
+				assert.ErrorIs(t, err, tt.expectedIs)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResponse, resp)
+		})
+	}
+}
+
+func TestClientGetWeatherWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"cep":"26140040","units":["K","R"],"precision":2,"scientific_kelvin":true}`, string(body))
+		io.WriteString(w, `{"city":"Belford Roxo","temp_K":304.35,"temp_R":547.83}`)
+	}))
+	defer server.Close()
+
+	precision := 2
+	resp, err := New(WithBaseURL(server.URL)).GetWeatherWithOptions(context.Background(), WeatherRequest{
+		CEP:              "26140040",
+		Units:            []string{"K", "R"},
+		Precision:        &precision,
+		ScientificKelvin: true,
+	})
+	require.NoError(t, err)
+	// Escalas fora da resposta ficam nil, e não 0
+	assert.Equal(t, &WeatherResponse{City: "Belford Roxo", TempK: float(304.35), TempR: float(547.83)}, resp)
+}
+
+func TestClientAuthAndUserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-API-Key"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "billing/1.0", r.Header.Get("User-Agent"))
+		io.WriteString(w, `{"city":"Belford Roxo"}`)
+	}))
+	defer server.Close()
+
+	_, err := New(
+		WithBaseURL(server.URL),
+		WithAPIKey("secret"),
+		WithBearerToken("token"),
+		WithUserAgent("billing/1.0"),
+	).GetWeather(context.Background(), "26140040")
+	require.NoError(t, err)
+}
+
+func TestClientRetries(t *testing.T) {
+	tests := []struct {
+		name             string
+		opts             []Option
+		statuses         []int
+		retryAfter       string
+		expectedAttempts int
+		expectedWaits    []time.Duration
+		expectedIs       error
+	}{
+		{
+			name:             "retries until success with exponential backoff",
+			opts:             []Option{WithRetries(3, 50*time.Millisecond)},
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			expectedAttempts: 3,
+			expectedWaits:    []time.Duration{50 * time.Millisecond, 100 * time.Millisecond},
+		},
+		{
+			name:             "gives up after retries",
+			opts:             []Option{WithRetries(1, 0)},
+			statuses:         []int{http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusOK},
+			expectedAttempts: 2,
+			expectedWaits:    []time.Duration{100 * time.Millisecond},
+			expectedIs:       ErrServer,
+		},
+		{
+			name:             "does not retry client errors",
+			opts:             []Option{WithRetries(3, 0)},
+			statuses:         []int{http.StatusNotFound, http.StatusOK},
+			expectedAttempts: 1,
+			expectedIs:       ErrNotFound,
+		},
+		{
+			name:             "does not retry internal server error",
+			opts:             []Option{WithRetries(3, 0)},
+			statuses:         []int{http.StatusInternalServerError, http.StatusOK},
+			expectedAttempts: 1,
+			expectedIs:       ErrServer,
+		},
+		{
+			name:             "honors retry after",
+			opts:             []Option{WithRetries(3, 0)},
+			statuses:         []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:       "2",
+			expectedAttempts: 2,
+			expectedWaits:    []time.Duration{2 * time.Second},
+		},
+		{
+			name:             "retry after beyond max wait",
+			opts:             []Option{WithRetries(3, 0), WithMaxRetryWait(time.Second)},
+			statuses:         []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:       "86400",
+			expectedAttempts: 1,
+			expectedIs:       ErrRateLimited,
+		},
+		{
+			name:             "no retries by default",
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectedAttempts: 1,
+			expectedIs:       ErrServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.JSONEq(t, `{"cep":"26140040"}`, string(body), "body is resent on every attempt")
+
+				status := tt.statuses[attempts.Add(1)-1]
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(status)
+				io.WriteString(w, `{"city":"Belford Roxo","message":"failed"}`)
+			}))
+			defer server.Close()
+
+			c := New(append([]Option{WithBaseURL(server.URL)}, tt.opts...)...)
+			var waits []time.Duration
+			c.sleep = func(_ context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+
+			_, err := c.GetWeather(context.Background(), "26140040")
+			assert.EqualValues(t, tt.expectedAttempts, attempts.Load())
+			assert.Equal(t, tt.expectedWaits, waits)
+			if tt.expectedIs != nil {
+				assert.ErrorIs(t, err, tt.expectedIs)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// roundTripperFunc adapta uma função a http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestClientRetriesTransportErrors(t *testing.T) {
+	var attempts int
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, errors.New("connection reset by peer")
+	})
+
+	c := New(WithTransport(transport), WithRetries(2, time.Millisecond))
+	_, err := c.GetWeather(context.Background(), "26140040")
+	assert.ErrorContains(t, err, "connection reset by peer")
+	assert.Equal(t, 3, attempts)
+
+	// Com o ctx cancelado não há novas tentativas
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	attempts = 0
+	_, err = c.GetWeather(ctx, "26140040")
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestClientPropagatesTraceContext(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		io.WriteString(w, `{"city":"Belford Roxo"}`)
+	}))
+	defer server.Close()
+
+	// Sem TracerProvider configurado, o contexto recebido do chamador segue adiante
+	parent := propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	})
+	require.True(t, trace.SpanContextFromContext(parent).IsValid())
+
+	_, err := New(WithBaseURL(server.URL), WithPropagators(propagation.TraceContext{})).GetWeather(parent, "26140040")
+	require.NoError(t, err)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", traceparent)
+}
+
+func float(v float64) *float64 {
+	return &v
+}
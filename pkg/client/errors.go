@@ -0,0 +1,72 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Erros por classe de status, para uso com errors.Is; o *Error devolvido pelo
+// cliente corresponde ao sentinel do seu status
+var (
+	// ErrBadRequest: 400, 413 ou 415 (body malformado, grande demais ou media type)
+	ErrBadRequest = errors.New("bad request")
+	// ErrInvalidZipcode: 422, o CEP não tem 8 dígitos
+	ErrInvalidZipcode = errors.New("invalid zipcode")
+	// ErrNotFound: 404, o CEP não existe
+	ErrNotFound = errors.New("zipcode not found")
+	// ErrUnauthorized: 401, credencial ausente ou inválida
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden: 403, a credencial não tem o scope exigido pela rota
+	ErrForbidden = errors.New("forbidden")
+	// ErrRateLimited: 429, rate limit ou quota excedidos (veja Error.RetryAfter)
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer: 5xx, falha no Service A ou nas dependências
+	ErrServer = errors.New("server error")
+)
+
+// Error é uma resposta de erro do Service A ({"message": ...})
+type Error struct {
+	StatusCode int
+	Message    string
+	// Fields detalha os campos inválidos do body, quando houver
+	Fields []FieldError
+	// RetryAfter vem do header Retry-After das respostas 429 e 503
+	RetryAfter time.Duration
+}
+
+// FieldError é um campo inválido do body ({"field": ..., "message": ...})
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("service A returned %d: %s", e.StatusCode, e.Message)
+}
+
+// Is associa o status ao sentinel correspondente (errors.Is(err, client.ErrNotFound))
+func (e *Error) Is(target error) bool {
+	return target != nil && statusError(e.StatusCode) == target
+}
+
+func statusError(status int) error {
+	switch {
+	case status == http.StatusUnprocessableEntity:
+		return ErrInvalidZipcode
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case status == http.StatusForbidden:
+		return ErrForbidden
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status >= 500:
+		return ErrServer
+	case status >= 400:
+		return ErrBadRequest
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultBaseURL é a URL usada sem WithBaseURL (Service A local)
+const DefaultBaseURL = "http://localhost:8080"
+
+// Option customiza o Client
+type Option func(*Client)
+
+// WithBaseURL define a URL base do Service A (padrão DefaultBaseURL)
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithTimeout limita cada tentativa (padrão 10s); o ctx das chamadas limita o total
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithAPIKey envia a chave em X-API-Key
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithBearerToken envia o token (ex: JWT) em "Authorization: Bearer"
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.bearerToken = token
+	}
+}
+
+// WithRetries repete até retries vezes as chamadas que falham na conexão ou com
+// 429, 502, 503 ou 504. A espera dobra a cada tentativa a partir de backoff
+// (padrão 100ms) até 2s, ou segue o Retry-After da resposta.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		if backoff > 0 {
+			c.backoff = backoff
+		}
+	}
+}
+
+// WithMaxRetryWait é a maior espera aceita entre tentativas (padrão 10s): um
+// Retry-After maior (ex: quota diária esgotada) encerra as tentativas
+func WithMaxRetryWait(wait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetryWait = wait
+	}
+}
+
+// WithTransport substitui o transport HTTP (padrão http.DefaultTransport); ele
+// é sempre envolvido pela instrumentação otelhttp
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithUserAgent define o header User-Agent
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithTracerProvider usa o provider informado nos spans do cliente em vez do global
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *Client) {
+		c.otelOpts = append(c.otelOpts, otelhttp.WithTracerProvider(provider))
+	}
+}
+
+// WithPropagators usa os propagators informados em vez do global
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *Client) {
+		c.otelOpts = append(c.otelOpts, otelhttp.WithPropagators(propagators))
+	}
+}
@@ -0,0 +1,305 @@
+// Package compress comprime respostas HTTP com brotli ou gzip conforme o
+// Accept-Encoding do cliente, apenas para tipos de conteúdo compressíveis e
+// corpos acima de um tamanho mínimo.
+package compress
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Encodings suportados, em ordem de preferência do servidor
+const (
+	EncodingBrotli = "br"
+	EncodingGzip   = "gzip"
+)
+
+// DefaultMinSize é o tamanho mínimo do corpo para compensar a compressão
+const DefaultMinSize = 1024
+
+// DefaultContentTypes são os tipos comprimidos por padrão; itens terminados em
+// "/" casam com qualquer subtipo (ex: text/)
+var DefaultContentTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"application/xml",
+	"application/javascript",
+	"text/",
+}
+
+// Option customiza o Compressor
+type Option func(*Compressor)
+
+// WithMinSize define o tamanho mínimo do corpo comprimido
+func WithMinSize(n int) Option {
+	return func(c *Compressor) {
+		c.minSize = n
+	}
+}
+
+// WithContentTypes substitui os tipos de conteúdo comprimidos
+func WithContentTypes(types ...string) Option {
+	return func(c *Compressor) {
+		c.contentTypes = types
+	}
+}
+
+// WithEncodings restringe os encodings oferecidos, em ordem de preferência
+func WithEncodings(encodings ...string) Option {
+	return func(c *Compressor) {
+		c.encodings = encodings
+	}
+}
+
+// Compressor decide por resposta se e como comprimir; é seguro para uso concorrente
+type Compressor struct {
+	minSize      int
+	contentTypes []string
+	encodings    []string
+	gzipPool     sync.Pool
+	brotliPool   sync.Pool
+}
+
+func New(opts ...Option) *Compressor {
+	c := &Compressor{
+		minSize:      DefaultMinSize,
+		contentTypes: DefaultContentTypes,
+		encodings:    []string{EncodingBrotli, EncodingGzip},
+	}
+	c.gzipPool.New = func() interface{} {
+		return gzip.NewWriter(io.Discard)
+	}
+	c.brotliPool.New = func() interface{} {
+		return brotli.NewWriterLevel(io.Discard, brotli.DefaultCompression)
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Middleware comprime a resposta de next quando o cliente aceita um dos encodings
+func (c *Compressor) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := c.negotiate(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &responseWriter{ResponseWriter: w, compressor: c, encoding: encoding, status: http.StatusOK}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiate escolhe o encoding de maior qualidade; em empate vale a ordem do servidor
+func (c *Compressor) negotiate(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	qualities := make(map[string]float64)
+	for _, item := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		qualities[strings.ToLower(strings.TrimSpace(name))] = quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range c.encodings {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality, ok = qualities["*"]
+		}
+		if ok && quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+func (c *Compressor) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range c.contentTypes {
+		if mediaType == t || (strings.HasSuffix(t, "/") && strings.HasPrefix(mediaType, t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// encoder devolve ao pool os writers de compressão ao ser fechado
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+type pooledGzip struct {
+	*gzip.Writer
+	pool *sync.Pool
+}
+
+func (g pooledGzip) Close() error {
+	err := g.Writer.Close()
+	g.pool.Put(g.Writer)
+	return err
+}
+
+type pooledBrotli struct {
+	*brotli.Writer
+	pool *sync.Pool
+}
+
+func (b pooledBrotli) Close() error {
+	err := b.Writer.Close()
+	b.pool.Put(b.Writer)
+	return err
+}
+
+func (c *Compressor) newEncoder(encoding string, w io.Writer) encoder {
+	if encoding == EncodingBrotli {
+		bw := c.brotliPool.Get().(*brotli.Writer)
+		bw.Reset(w)
+		return pooledBrotli{Writer: bw, pool: &c.brotliPool}
+	}
+	gw := c.gzipPool.Get().(*gzip.Writer)
+	gw.Reset(w)
+	return pooledGzip{Writer: gw, pool: &c.gzipPool}
+}
+
+// responseWriter bufferiza o início do corpo até saber se vale comprimir:
+// o tipo de conteúdo precisa ser compressível e o corpo atingir o tamanho mínimo
+type responseWriter struct {
+	http.ResponseWriter
+	compressor *Compressor
+	encoding   string
+
+	status  int
+	buf     []byte
+	decided bool
+	encoder encoder
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.decided {
+		return
+	}
+	// Respostas informativas (1xx) seguem direto; o status final ainda virá
+	if status >= 100 && status < 200 {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		w.decide(false)
+	}
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, p...)
+		if len(w.buf) < w.compressor.minSize {
+			return len(p), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush força a decisão com o que já foi escrito (streaming) e esvazia o encoder
+func (w *responseWriter) Flush() {
+	if !w.decided {
+		w.decide(len(w.buf) >= w.compressor.minSize)
+	}
+	if w.encoder != nil {
+		w.encoder.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack permite upgrades de protocolo (ex: WebSocket) sem compressão
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("compress: underlying ResponseWriter does not implement http.Hijacker")
+	}
+	w.decided = true
+	return hijacker.Hijack()
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide envia os headers e o buffer, comprimindo se permitido e elegível
+func (w *responseWriter) decide(allowed bool) error {
+	w.decided = true
+
+	header := w.Header()
+	if header.Get("Content-Type") == "" && len(w.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if allowed && header.Get("Content-Encoding") == "" && w.compressor.compressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		w.encoder = w.compressor.newEncoder(w.encoding, w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	buf := w.buf
+	w.buf = nil
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+func (w *responseWriter) close() {
+	if !w.decided {
+		// Corpo menor que o mínimo: segue sem compressão
+		w.decide(false)
+	}
+	if w.encoder != nil {
+		w.encoder.Close()
+	}
+}
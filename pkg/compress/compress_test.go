@@ -0,0 +1,225 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressorMiddleware(t *testing.T) {
+	large := `{"city":"` + strings.Repeat("Belford Roxo ", 200) + `"}`
+	small := `{"city":"Belford Roxo"}`
+
+	tests := []struct {
+		name             string
+		opts             []Option
+		acceptEncoding   string
+		contentType      string
+		contentEncoding  string
+		status           int
+		body             string
+		expectedEncoding string
+	}{
+		{
+			name:             "gzip",
+			acceptEncoding:   "gzip",
+			contentType:      "application/json",
+			body:             large,
+			expectedEncoding: EncodingGzip,
+		},
+		{
+			name:             "brotli preferred on equal quality",
+			acceptEncoding:   "gzip, deflate, br",
+			contentType:      "application/json",
+			body:             large,
+			expectedEncoding: EncodingBrotli,
+		},
+		{
+			name:             "highest quality wins",
+			acceptEncoding:   "br;q=0.5, gzip;q=0.8",
+			contentType:      "application/json",
+			body:             large,
+			expectedEncoding: EncodingGzip,
+		},
+		{
+			name:             "wildcard",
+			acceptEncoding:   "*",
+			contentType:      "text/csv",
+			body:             large,
+			expectedEncoding: EncodingBrotli,
+		},
+		{
+			name:           "encoding refused with q zero",
+			acceptEncoding: "br;q=0, gzip;q=0",
+			contentType:    "application/json",
+			body:           large,
+		},
+		{
+			name:        "no accept encoding",
+			contentType: "application/json",
+			body:        large,
+		},
+		{
+			name:           "body below minimum size",
+			acceptEncoding: "gzip",
+			contentType:    "application/json",
+			body:           small,
+		},
+		{
+			name:             "custom minimum size",
+			opts:             []Option{WithMinSize(10)},
+			acceptEncoding:   "gzip",
+			contentType:      "application/json",
+			body:             small,
+			expectedEncoding: EncodingGzip,
+		},
+		{
+			name:           "content type not compressible",
+			acceptEncoding: "gzip",
+			contentType:    "application/x-protobuf",
+			body:           large,
+		},
+		{
+			name:             "custom content types",
+			opts:             []Option{WithContentTypes("application/x-protobuf")},
+			acceptEncoding:   "gzip",
+			contentType:      "application/x-protobuf",
+			body:             large,
+			expectedEncoding: EncodingGzip,
+		},
+		{
+			name:             "restricted encodings",
+			opts:             []Option{WithEncodings(EncodingGzip)},
+			acceptEncoding:   "br, gzip",
+			contentType:      "application/json",
+			body:             large,
+			expectedEncoding: EncodingGzip,
+		},
+		{
+			name:            "already encoded",
+			acceptEncoding:  "gzip",
+			contentType:     "application/json",
+			contentEncoding: "identity-custom",
+			body:            large,
+		},
+		{
+			name:             "error status is compressed too",
+			acceptEncoding:   "gzip",
+			contentType:      "application/json",
+			status:           http.StatusInternalServerError,
+			body:             large,
+			expectedEncoding: EncodingGzip,
+		},
+		{
+			name:           "no content",
+			acceptEncoding: "gzip",
+			status:         http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := New(tt.opts...).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				if tt.contentEncoding != "" {
+					w.Header().Set("Content-Encoding", tt.contentEncoding)
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				// Escreve em pedaços para exercitar o buffer até o tamanho mínimo
+				for i := 0; i < len(tt.body); i += 100 {
+					io.WriteString(w, tt.body[i:min(i+100, len(tt.body))])
+				}
+			}))
+
+			req := httptest.NewRequest("GET", "/weather", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			expectedStatus := tt.status
+			if expectedStatus == 0 {
+				expectedStatus = http.StatusOK
+			}
+			assert.Equal(t, expectedStatus, rec.Code)
+			assert.Contains(t, rec.Header().Values("Vary"), "Accept-Encoding")
+
+			encoding := rec.Header().Get("Content-Encoding")
+			if tt.contentEncoding != "" {
+				assert.Equal(t, tt.contentEncoding, encoding)
+				assert.Equal(t, tt.body, rec.Body.String())
+				return
+			}
+			assert.Equal(t, tt.expectedEncoding, encoding)
+			assert.Equal(t, tt.body, decompress(t, encoding, rec.Body))
+			if tt.expectedEncoding != "" {
+				assert.Less(t, rec.Body.Len(), len(tt.body))
+			}
+		})
+	}
+}
+
+func TestCompressorFlush(t *testing.T) {
+	// Um Flush antes do tamanho mínimo envia os headers sem compressão
+	handler := New().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: 1\n\n")
+		w.(http.Flusher).Flush()
+		io.WriteString(w, "data: 2\n\n")
+	}))
+
+	req := httptest.NewRequest("GET", "/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.True(t, rec.Flushed)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "data: 1\n\ndata: 2\n\n", rec.Body.String())
+}
+
+func TestCompressorDetectsContentType(t *testing.T) {
+	handler := New().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, strings.Repeat("plain text ", 200))
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, EncodingGzip, rec.Header().Get("Content-Encoding"))
+}
+
+func decompress(t *testing.T, encoding string, body io.Reader) string {
+	t.Helper()
+
+	var reader io.Reader
+	switch encoding {
+	case EncodingGzip:
+		gr, err := gzip.NewReader(body)
+		require.NoError(t, err)
+		reader = gr
+	case EncodingBrotli:
+		reader = brotli.NewReader(body)
+	default:
+		reader = body
+	}
+
+	out, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(out)
+}
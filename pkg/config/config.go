@@ -0,0 +1,244 @@
+// Package config carrega as configurações tipadas dos serviços a partir do
+// viper (variáveis de ambiente e .env), valida todos os campos de uma vez, para
+// que o erro de inicialização liste todos os problemas, e imprime a
+// configuração efetiva com os segredos mascarados.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// Validatable é implementado pelas structs de configuração dos serviços
+type Validatable interface {
+	Validate(v *Validator)
+}
+
+// Error lista todos os problemas encontrados na configuração
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Load preenche cfg com os valores do viper (tags mapstructure com o nome das
+// variáveis em minúsculas) e valida o resultado. Valores que não podem ser
+// convertidos e falhas de validação são reunidos em um único *Error; cfg fica
+// preenchido com o que foi possível ler, para uso em --print-config.
+func Load(v *viper.Viper, cfg Validatable) error {
+	validator := &Validator{}
+
+	err := v.Unmarshal(cfg, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		stringToListHook,
+	)))
+	validator.decodeErrors(v, err)
+
+	cfg.Validate(validator)
+	return validator.Err()
+}
+
+// stringToListHook converte "a, b,,c" em []string{"a", "b", "c"}
+func stringToListHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String || to != reflect.TypeOf([]string{}) {
+		return data, nil
+	}
+	return SplitList(data.(string)), nil
+}
+
+// SplitList separa uma lista por vírgulas, ignorando espaços e itens vazios
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Validator acumula os problemas da configuração; as chaves são os nomes das
+// variáveis de ambiente (ex: SERVICE_B_URL)
+type Validator struct {
+	problems []string
+	// undecoded são as chaves cujo valor não pôde ser convertido; as demais
+	// regras dessas chaves são ignoradas, pois veriam apenas o zero value
+	undecoded map[string]bool
+}
+
+// Err devolve um *Error com os problemas acumulados ou nil
+func (v *Validator) Err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &Error{Problems: v.problems}
+}
+
+// Addf registra um problema na chave
+func (v *Validator) Addf(key, format string, args ...interface{}) {
+	if v.undecoded[key] {
+		return
+	}
+	v.problems = append(v.problems, key+": "+fmt.Sprintf(format, args...))
+}
+
+// Check registra err, se houver (ex: erros dos parsers de cada formato)
+func (v *Validator) Check(key string, err error) {
+	if err != nil {
+		v.Addf(key, "%v", err)
+	}
+}
+
+// Required exige um valor não vazio
+func (v *Validator) Required(key, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.Addf(key, "is required")
+	}
+}
+
+// URL exige uma URL absoluta com um dos esquemas (padrão http e https); vazio é aceito
+func (v *Validator) URL(key, value string, schemes ...string) {
+	if value == "" {
+		return
+	}
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" || !slices.Contains(schemes, u.Scheme) {
+		v.Addf(key, "must be a %s URL with host, got %q", strings.Join(schemes, " or "), redact(value))
+	}
+}
+
+// HostPort exige host:porta (ex: service-b:9091)
+func (v *Validator) HostPort(key, value string) {
+	if _, port, err := net.SplitHostPort(value); err != nil || port == "" {
+		v.Addf(key, "must be host:port, got %q", value)
+	}
+}
+
+// Loopback exige host:porta com host de loopback (localhost, 127.0.0.1 ou ::1),
+// para endpoints que não podem ser expostos na rede
+func (v *Validator) Loopback(key, value string) {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil || port == "" {
+		v.Addf(key, "must be host:port, got %q", value)
+		return
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		v.Addf(key, "must be a loopback address (localhost, 127.0.0.1 or ::1), got %q", value)
+	}
+}
+
+// CIDRs exige que cada item seja um bloco CIDR (ex: 10.0.0.0/8, fd00::/8)
+func (v *Validator) CIDRs(key string, values []string) {
+	for _, value := range values {
+		if _, err := netip.ParsePrefix(value); err != nil {
+			v.Addf(key, "entry %q must be a CIDR block (ex: 10.0.0.0/8)", value)
+		}
+	}
+}
+
+// Port exige uma porta TCP entre 1 e 65535
+func (v *Validator) Port(key string, port int) {
+	if port < 1 || port > 65535 {
+		v.Addf(key, "must be between 1 and 65535, got %d", port)
+	}
+}
+
+// Positive exige uma duração maior que zero
+func (v *Validator) Positive(key string, d time.Duration) {
+	if d <= 0 {
+		v.Addf(key, "must be a positive duration, got %s", d)
+	}
+}
+
+// NonNegative exige uma duração maior ou igual a zero
+func (v *Validator) NonNegative(key string, d time.Duration) {
+	if d < 0 {
+		v.Addf(key, "must not be negative, got %s", d)
+	}
+}
+
+// Min exige um número maior ou igual a min
+func (v *Validator) Min(key string, value, min int64) {
+	if value < min {
+		v.Addf(key, "must be at least %d, got %d", min, value)
+	}
+}
+
+// Fraction exige um número entre 0 e 1, exclusive
+func (v *Validator) Fraction(key string, value float64) {
+	if value <= 0 || value >= 1 {
+		v.Addf(key, "must be between 0 and 1 (exclusive), got %g", value)
+	}
+}
+
+// File exige que o arquivo exista; vazio é aceito
+func (v *Validator) File(key, path string) {
+	if path == "" {
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		v.Addf(key, "%v", err)
+	}
+}
+
+// OneOf exige um dos valores permitidos
+func (v *Validator) OneOf(key, value string, allowed ...string) {
+	if !slices.Contains(allowed, value) {
+		v.Addf(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+	}
+}
+
+// Together exige que as chaves sejam definidas juntas (ex: certificado e chave)
+func (v *Validator) Together(keys []string, values ...string) {
+	set := 0
+	for _, value := range values {
+		if value != "" {
+			set++
+		}
+	}
+	if set > 0 && set < len(values) {
+		v.Addf(strings.Join(keys, ", "), "must be set together")
+	}
+}
+
+// decodeErrors registra cada campo que o viper não conseguiu converter, com o
+// valor lido (as mensagens do mapstructure o omitem); os erros de campo
+// (mapstructure.DecodeError) chegam reunidos por errors.Join
+func (v *Validator) decodeErrors(config *viper.Viper, err error) {
+	switch e := err.(type) {
+	case nil:
+	case *mapstructure.DecodeError:
+		key := strings.ToUpper(e.Name())
+		v.Addf(key, "invalid value %q: %v", redact(fmt.Sprint(config.Get(e.Name()))), e.Unwrap())
+		if v.undecoded == nil {
+			v.undecoded = make(map[string]bool)
+		}
+		v.undecoded[key] = true
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			v.decodeErrors(config, err)
+		}
+	default:
+		if wrapped := errors.Unwrap(err); wrapped != nil {
+			v.decodeErrors(config, wrapped)
+			return
+		}
+		v.problems = append(v.problems, err.Error())
+	}
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/fault"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/replay"
+)
+
+// Grupos de configuração comuns aos dois serviços, incorporados com
+// `mapstructure:",squash"` para manter os nomes planos das variáveis
+
+// Server são os timeouts do servidor HTTP e o prazo de cada requisição
+type Server struct {
+	// ReadTimeout e WriteTimeout são os do http.Server (0 = sem limite)
+	ReadTimeout     time.Duration `mapstructure:"server_read_timeout"`
+	WriteTimeout    time.Duration `mapstructure:"server_write_timeout"`
+	ShutdownTimeout time.Duration `mapstructure:"server_shutdown_timeout"`
+	// RequestTimeout é o orçamento de cada requisição, repassado às
+	// dependências no header X-Request-Timeout (0 = apenas o do chamador)
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+}
+
+func (c Server) Validate(v *Validator) {
+	v.NonNegative("SERVER_READ_TIMEOUT", c.ReadTimeout)
+	v.NonNegative("SERVER_WRITE_TIMEOUT", c.WriteTimeout)
+	v.Positive("SERVER_SHUTDOWN_TIMEOUT", c.ShutdownTimeout)
+	v.NonNegative("REQUEST_TIMEOUT", c.RequestTimeout)
+	// Com o prazo estourado o serviço ainda precisa escrever a resposta de
+	// timeout; com 0 o prazo fica apenas com o chamador
+	if c.WriteTimeout > 0 && c.RequestTimeout >= c.WriteTimeout {
+		v.Addf("REQUEST_TIMEOUT", "must be shorter than SERVER_WRITE_TIMEOUT (%s), got %s", c.WriteTimeout, c.RequestTimeout)
+	}
+}
+
+// MaxRequestTimeout é o maior prazo aceito do chamador em X-Request-Timeout:
+// 90% de WriteTimeout, deixando tempo para escrever a resposta de timeout antes
+// de a conexão ser cortada (0 = sem limite)
+func (c Server) MaxRequestTimeout() time.Duration {
+	return c.WriteTimeout - c.WriteTimeout/10
+}
+
+// TLS é o HTTPS do servidor (TLS_*); sem certificado o servidor usa HTTP
+type TLS struct {
+	CertFile string `mapstructure:"tls_cert_file"`
+	KeyFile  string `mapstructure:"tls_key_file"`
+	// CAFile torna obrigatório o certificado de cliente emitido pela CA (mTLS)
+	CAFile string `mapstructure:"tls_ca_file"`
+	// AllowedClients restringe as identidades de cliente aceitas (CN, SAN DNS ou URI)
+	AllowedClients []string      `mapstructure:"tls_allowed_clients"`
+	ReloadInterval time.Duration `mapstructure:"tls_reload_interval"`
+}
+
+func (c TLS) Validate(v *Validator) {
+	v.Together([]string{"TLS_CERT_FILE", "TLS_KEY_FILE"}, c.CertFile, c.KeyFile)
+	v.File("TLS_CERT_FILE", c.CertFile)
+	v.File("TLS_KEY_FILE", c.KeyFile)
+	v.File("TLS_CA_FILE", c.CAFile)
+	if c.CAFile != "" && c.CertFile == "" {
+		v.Addf("TLS_CA_FILE", "requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+	if len(c.AllowedClients) > 0 && c.CAFile == "" {
+		v.Addf("TLS_ALLOWED_CLIENTS", "requires TLS_CA_FILE")
+	}
+	v.Positive("TLS_RELOAD_INTERVAL", c.ReloadInterval)
+}
+
+// Replay grava ou reproduz as chamadas HTTP dos clientes (HTTP_REPLAY_*)
+type Replay struct {
+	Mode replay.Mode `mapstructure:"http_replay_mode"`
+	Dir  string      `mapstructure:"http_replay_dir"`
+}
+
+func (c Replay) Validate(v *Validator) {
+	mode, err := replay.ParseMode(string(c.Mode))
+	v.Check("HTTP_REPLAY_MODE", err)
+	if err == nil && mode != replay.ModeOff {
+		v.Required("HTTP_REPLAY_DIR", c.Dir)
+	}
+}
+
+// Faults são as falhas injetadas nos clientes das dependências (FAULTS, em JSON)
+type Faults struct {
+	Rules        string `mapstructure:"faults"`
+	AdminEnabled bool   `mapstructure:"fault_admin_enabled"`
+}
+
+// Validate confere as regras; com dependencies, apenas esses nomes são aceitos
+func (c Faults) Validate(v *Validator, dependencies ...string) {
+	_, err := fault.ParseRules(c.Rules, dependencies...)
+	v.Check("FAULTS", err)
+}
+
+// HTTP reúne o processamento das requisições e respostas do servidor
+type HTTP struct {
+	MaxBodyBytes             int64    `mapstructure:"max_body_bytes"`
+	JSONAllowUnknownFields   bool     `mapstructure:"json_allow_unknown_fields"`
+	OpenAPIValidateResponses bool     `mapstructure:"openapi_validate_responses"`
+	CompressionEnabled       bool     `mapstructure:"compression_enabled"`
+	CompressionMinBytes      int      `mapstructure:"compression_min_bytes"`
+	CompressionContentTypes  []string `mapstructure:"compression_content_types"`
+	H2CEnabled               bool     `mapstructure:"h2c_enabled"`
+}
+
+func (c HTTP) Validate(v *Validator) {
+	v.Min("MAX_BODY_BYTES", c.MaxBodyBytes, 1)
+	v.Min("COMPRESSION_MIN_BYTES", int64(c.CompressionMinBytes), 0)
+}
+
+// Health controla o cache e o timeout das verificações de /readyz
+type Health struct {
+	CacheTTL     time.Duration `mapstructure:"health_cache_ttl"`
+	CheckTimeout time.Duration `mapstructure:"health_check_timeout"`
+}
+
+func (c Health) Validate(v *Validator) {
+	v.NonNegative("HEALTH_CACHE_TTL", c.CacheTTL)
+	v.Positive("HEALTH_CHECK_TIMEOUT", c.CheckTimeout)
+}
+
+// Telemetry sobrescreve as variáveis OTEL_* padrão do SDK
+type Telemetry struct {
+	ZipkinURL   string   `mapstructure:"zipkin_url"`
+	Propagators []string `mapstructure:"otel_propagators"`
+	Environment string   `mapstructure:"deployment_environment"`
+}
+
+func (c Telemetry) Validate(v *Validator) {
+	v.URL("ZIPKIN_URL", c.ZipkinURL)
+	_, err := otel.NewPropagator(c.Propagators)
+	v.Check("OTEL_PROPAGATORS", err)
+}
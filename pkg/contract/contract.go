@@ -0,0 +1,275 @@
+// Package contract implementa contratos dirigidos pelo consumidor: o consumidor
+// publica as interações de que depende (requisição e resposta esperada) e o
+// provedor verifica seu handler real contra elas.
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Contract é o arquivo publicado pelo consumidor
+type Contract struct {
+	Consumer     string        `json:"consumer"`
+	Provider     string        `json:"provider"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction descreve uma requisição e a resposta de que o consumidor depende.
+// ProviderState nomeia a situação que o provedor precisa preparar antes de verificar.
+type Interaction struct {
+	Description   string   `json:"description"`
+	ProviderState string   `json:"provider_state,omitempty"`
+	Request       Request  `json:"request"`
+	Response      Response `json:"response"`
+}
+
+type Request struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// Response é comparada por tipo: o provedor precisa devolver cada campo do
+// exemplo com o mesmo tipo JSON (campos extras são permitidos). Os campos de
+// Exact também precisam ter o mesmo valor.
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+	Exact   []string          `json:"exact,omitempty"`
+}
+
+// Load lê um contrato publicado
+func Load(path string) (*Contract, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading contract: %w", err)
+	}
+	var c Contract
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("error parsing contract %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Marshal serializa o contrato no formato publicado
+func (c *Contract) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Write publica o contrato em path
+func (c *Contract) Write(path string) error {
+	data, err := c.Marshal()
+	if err != nil {
+		return fmt.Errorf("error encoding contract: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Verify envia a requisição da interação ao handler do provedor e devolve as
+// divergências em relação à resposta esperada
+func Verify(handler http.Handler, interaction Interaction) error {
+	req := httptest.NewRequest(interaction.Request.Method, interaction.Request.Path, bytes.NewReader(interaction.Request.Body))
+	for name, value := range interaction.Request.Headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	expected := interaction.Response
+	var problems []error
+	if rec.Code != expected.Status {
+		problems = append(problems, fmt.Errorf("status: expected %d, got %d", expected.Status, rec.Code))
+	}
+	for name, value := range expected.Headers {
+		if actual := rec.Header().Get(name); !headerMatches(name, value, actual) {
+			problems = append(problems, fmt.Errorf("header %s: expected %q, got %q", name, value, actual))
+		}
+	}
+	if len(expected.Body) > 0 {
+		problems = append(problems, matchBody(expected, rec.Body.Bytes())...)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s: %w", interaction.Description, errors.Join(problems...))
+	}
+	return nil
+}
+
+// headerMatches compara Content-Type pelo media type, ignorando parâmetros como charset
+func headerMatches(name, expected, actual string) bool {
+	if !strings.EqualFold(name, "Content-Type") {
+		return expected == actual
+	}
+	expectedType, _, err := mime.ParseMediaType(expected)
+	if err != nil {
+		return false
+	}
+	actualType, _, err := mime.ParseMediaType(actual)
+	return err == nil && expectedType == actualType
+}
+
+func matchBody(expected Response, body []byte) []error {
+	var want, got interface{}
+	if err := json.Unmarshal(expected.Body, &want); err != nil {
+		return []error{fmt.Errorf("invalid expected body: %w", err)}
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		return []error{fmt.Errorf("body is not JSON: %q", body)}
+	}
+
+	problems := matchType("$", want, got)
+	for _, field := range expected.Exact {
+		wantValue, _ := lookup(want, field)
+		gotValue, ok := lookup(got, field)
+		if !ok || !reflect.DeepEqual(wantValue, gotValue) {
+			problems = append(problems, fmt.Errorf("%s: expected %v, got %v", field, wantValue, gotValue))
+		}
+	}
+	return problems
+}
+
+// matchType exige em got cada campo de want com o mesmo tipo JSON
+func matchType(path string, want, got interface{}) []error {
+	if reflect.TypeOf(want) != reflect.TypeOf(got) {
+		return []error{fmt.Errorf("%s: expected %s, got %s", path, jsonType(want), jsonType(got))}
+	}
+
+	wantObject, ok := want.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	gotObject := got.(map[string]interface{})
+
+	keys := make([]string, 0, len(wantObject))
+	for key := range wantObject {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []error
+	for _, key := range keys {
+		value, ok := gotObject[key]
+		if !ok {
+			problems = append(problems, fmt.Errorf("%s.%s: missing", path, key))
+			continue
+		}
+		problems = append(problems, matchType(path+"."+key, wantObject[key], value)...)
+	}
+	return problems
+}
+
+// lookup segue um caminho com pontos (ex: "message" ou "error.code")
+func lookup(value interface{}, field string) (interface{}, bool) {
+	for _, key := range strings.Split(field, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// MockServer faz o papel do provedor nos testes do consumidor: responde com a
+// resposta da interação e registra se a requisição recebida diverge do contrato
+type MockServer struct {
+	*httptest.Server
+	interaction Interaction
+
+	mu       sync.Mutex
+	problems []error
+	calls    int
+}
+
+func NewMockServer(interaction Interaction) *MockServer {
+	m := &MockServer{interaction: interaction}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serve))
+	return m
+}
+
+func (m *MockServer) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	m.mu.Lock()
+	m.calls++
+	m.problems = append(m.problems, m.matchRequest(r, body)...)
+	m.mu.Unlock()
+
+	for name, value := range m.interaction.Response.Headers {
+		w.Header().Set(name, value)
+	}
+	w.WriteHeader(m.interaction.Response.Status)
+	w.Write(m.interaction.Response.Body)
+}
+
+func (m *MockServer) matchRequest(r *http.Request, body []byte) []error {
+	expected := m.interaction.Request
+	var problems []error
+	if r.Method != expected.Method || r.URL.Path != expected.Path {
+		problems = append(problems, fmt.Errorf("request: expected %s %s, got %s %s", expected.Method, expected.Path, r.Method, r.URL.Path))
+	}
+	for name, value := range expected.Headers {
+		if actual := r.Header.Get(name); !headerMatches(name, value, actual) {
+			problems = append(problems, fmt.Errorf("request header %s: expected %q, got %q", name, value, actual))
+		}
+	}
+	if len(expected.Body) > 0 {
+		var want, got interface{}
+		json.Unmarshal(expected.Body, &want)
+		if err := json.Unmarshal(body, &got); err != nil || !reflect.DeepEqual(want, got) {
+			problems = append(problems, fmt.Errorf("request body: expected %s, got %s", expected.Body, body))
+		}
+	}
+	return problems
+}
+
+// Err devolve as divergências das requisições recebidas ou erro se nenhuma chegou
+func (m *MockServer) Err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.calls == 0 {
+		return fmt.Errorf("%s: no request received", m.interaction.Description)
+	}
+	if len(m.problems) > 0 {
+		return fmt.Errorf("%s: %w", m.interaction.Description, errors.Join(m.problems...))
+	}
+	return nil
+}
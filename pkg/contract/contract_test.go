@@ -0,0 +1,123 @@
+package contract
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var notFound = Interaction{
+	Description:   "unknown zipcode",
+	ProviderState: "zipcode 99999999 does not exist",
+	Request: Request{
+		Method:  "POST",
+		Path:    "/weather",
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    json.RawMessage(`{"cep":"99999999"}`),
+	},
+	Response: Response{
+		Status:  http.StatusNotFound,
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    json.RawMessage(`{"message":"can not find zipcode","code":1}`),
+		Exact:   []string{"message"},
+	},
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		expectedErr []string
+	}{
+		{name: "matches", status: 404, contentType: "application/json; charset=utf-8", body: `{"message":"can not find zipcode","code":7,"extra":true}`},
+		{name: "wrong status", status: 500, contentType: "application/json", body: `{"message":"can not find zipcode","code":1}`, expectedErr: []string{"status: expected 404, got 500"}},
+		{name: "wrong content type", status: 404, contentType: "text/plain", body: `{"message":"can not find zipcode","code":1}`, expectedErr: []string{"header Content-Type"}},
+		{name: "wrong message", status: 404, contentType: "application/json", body: `{"message":"not found","code":1}`, expectedErr: []string{"message: expected can not find zipcode, got not found"}},
+		{name: "wrong type", status: 404, contentType: "application/json", body: `{"message":"can not find zipcode","code":"1"}`, expectedErr: []string{"$.code: expected number, got string"}},
+		{name: "missing field", status: 404, contentType: "application/json", body: `{"message":"can not find zipcode"}`, expectedErr: []string{"$.code: missing"}},
+		{name: "not json", status: 404, contentType: "application/json", body: `oops`, expectedErr: []string{"body is not JSON"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r.Method + " " + r.URL.Path + " " + r.Header.Get("Content-Type")
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			err := Verify(handler, notFound)
+
+			assert.Equal(t, "POST /weather application/json", received)
+			if len(tt.expectedErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range tt.expectedErr {
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestMockServer(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		body        string
+		expectedErr string
+	}{
+		{name: "matching request", path: "/weather", body: `{ "cep": "99999999" }`},
+		{name: "wrong path", path: "/clima", body: `{"cep":"99999999"}`, expectedErr: "expected POST /weather, got POST /clima"},
+		{name: "wrong body", path: "/weather", body: `{"cep":"26140040"}`, expectedErr: "request body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMockServer(notFound)
+			defer server.Close()
+
+			resp, err := http.Post(server.URL+tt.path, "application/json", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			if tt.expectedErr == "" {
+				assert.NoError(t, server.Err())
+				return
+			}
+			assert.ErrorContains(t, server.Err(), tt.expectedErr)
+		})
+	}
+
+	server := NewMockServer(notFound)
+	defer server.Close()
+	assert.ErrorContains(t, server.Err(), "no request received")
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contract.json")
+	c := &Contract{Consumer: "service-a", Provider: "service-b", Interactions: []Interaction{notFound}}
+
+	require.NoError(t, c.Write(path))
+	loaded, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, c.Consumer, loaded.Consumer)
+	require.Len(t, loaded.Interactions, 1)
+	assert.Equal(t, notFound.Response.Status, loaded.Interactions[0].Response.Status)
+	assert.JSONEq(t, string(notFound.Response.Body), string(loaded.Interactions[0].Response.Body))
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+// Package deadline faz o orçamento de tempo de cada requisição: o prazo é
+// definido na entrada (o menor entre o timeout do serviço e o recebido do
+// chamador), repassado às dependências no header X-Request-Timeout e dividido
+// entre as chamadas que a requisição faz em sequência.
+package deadline
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Header carrega o tempo restante da requisição, em milissegundos
+const Header = "X-Request-Timeout"
+
+// Middleware limita cada requisição a timeout ou, se for menor, ao prazo
+// recebido em Header; com timeout 0 vale apenas o do chamador. O prazo
+// recebido nunca passa de limit (0 = sem limite), normalmente o WriteTimeout
+// do servidor, para que um chamador não leve a requisição além do ponto em que
+// a conexão é cortada sem resposta. O orçamento é registrado no atributo
+// request.timeout_ms do span da requisição.
+func Middleware(timeout, limit time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			budget := timeout
+			if received, ok := Parse(r.Header.Get(Header)); ok && (budget <= 0 || received < budget) {
+				budget = received
+				if limit > 0 && budget > limit {
+					budget = limit
+				}
+			}
+			if budget <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			trace.SpanFromContext(r.Context()).SetAttributes(attribute.Int64("request.timeout_ms", budget.Milliseconds()))
+			ctx, cancel := context.WithTimeout(r.Context(), budget)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Parse lê o valor de Header; valores ausentes ou inválidos são ignorados
+func Parse(value string) (time.Duration, bool) {
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms <= 0 {
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+// Inject escreve em header o tempo restante até o prazo de ctx; sem prazo não
+// faz nada. O valor é arredondado para baixo, com no mínimo 1ms.
+func Inject(ctx context.Context, header http.Header) {
+	remaining, ok := Remaining(ctx)
+	if !ok {
+		return
+	}
+	ms := remaining.Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+	header.Set(Header, strconv.FormatInt(ms, 10))
+}
+
+// Remaining é o tempo até o prazo de ctx (zero se já passou)
+func Remaining(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	if remaining := time.Until(deadline); remaining > 0 {
+		return remaining, true
+	}
+	return 0, true
+}
+
+// Share reserva para uma chamada a fração (0 a 1) do tempo restante de ctx; as
+// chamadas seguintes ficam com o que sobrar. Sem prazo, ctx segue sem limite.
+func Share(ctx context.Context, fraction float64) (context.Context, context.CancelFunc) {
+	remaining, ok := Remaining(ctx)
+	if !ok || fraction <= 0 || fraction >= 1 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(float64(remaining)*fraction))
+}
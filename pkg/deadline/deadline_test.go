@@ -0,0 +1,97 @@
+package deadline
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeout     time.Duration
+		limit       time.Duration
+		header      string
+		expected    time.Duration
+		hasDeadline bool
+	}{
+		{name: "service timeout", timeout: 5 * time.Second, expected: 5 * time.Second, hasDeadline: true},
+		{name: "shorter caller budget", timeout: 5 * time.Second, header: "1500", expected: 1500 * time.Millisecond, hasDeadline: true},
+		{name: "longer caller budget is capped", timeout: 5 * time.Second, header: "60000", expected: 5 * time.Second, hasDeadline: true},
+		{name: "caller budget only", header: "2000", expected: 2 * time.Second, hasDeadline: true},
+		{name: "caller budget is capped by limit", limit: 3 * time.Second, header: "600000", expected: 3 * time.Second, hasDeadline: true},
+		{name: "caller budget below limit", limit: 3 * time.Second, header: "2000", expected: 2 * time.Second, hasDeadline: true},
+		{name: "limit alone adds no deadline", limit: 3 * time.Second},
+		{name: "invalid header is ignored", timeout: 5 * time.Second, header: "soon", expected: 5 * time.Second, hasDeadline: true},
+		{name: "negative header is ignored", timeout: 5 * time.Second, header: "-10", expected: 5 * time.Second, hasDeadline: true},
+		{name: "no limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				remaining   time.Duration
+				hasDeadline bool
+			)
+			handler := Middleware(tt.timeout, tt.limit)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				remaining, hasDeadline = Remaining(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/weather", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.hasDeadline, hasDeadline)
+			assert.InDelta(t, tt.expected, remaining, float64(100*time.Millisecond))
+		})
+	}
+}
+
+func TestInject(t *testing.T) {
+	header := http.Header{}
+	Inject(context.Background(), header)
+	assert.Empty(t, header.Get(Header), "no deadline, no header")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	Inject(ctx, header)
+	budget, ok := Parse(header.Get(Header))
+	require.True(t, ok)
+	assert.InDelta(t, 3*time.Second, budget, float64(100*time.Millisecond))
+
+	// Prazo vencido ainda envia 1ms, para que a dependência falhe rápido
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	Inject(expired, header)
+	assert.Equal(t, "1", header.Get(Header))
+}
+
+func TestShare(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+	defer cancel()
+
+	shared, cancelShared := Share(ctx, 0.25)
+	defer cancelShared()
+	remaining, ok := Remaining(shared)
+	require.True(t, ok)
+	assert.InDelta(t, time.Second, remaining, float64(100*time.Millisecond))
+
+	// Frações fora de (0, 1) mantêm o prazo original
+	whole, cancelWhole := Share(ctx, 1)
+	defer cancelWhole()
+	remaining, _ = Remaining(whole)
+	assert.InDelta(t, 4*time.Second, remaining, float64(100*time.Millisecond))
+
+	// Sem prazo, não há o que dividir
+	unbounded, cancelUnbounded := Share(context.Background(), 0.5)
+	defer cancelUnbounded()
+	_, ok = Remaining(unbounded)
+	assert.False(t, ok)
+}
@@ -0,0 +1,208 @@
+// Package decode lê bodies JSON de forma estrita: limita o tamanho, exige
+// application/json, rejeita campos desconhecidos e dados após o objeto e
+// valida as tags `validate` do destino.
+package decode
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// DefaultMaxBytes é o tamanho máximo padrão do body
+const DefaultMaxBytes = 64 << 10
+
+// FieldError descreve a falha de validação de um campo (nome do JSON)
+type FieldError struct {
+	Field   string `json:"field" xml:"field"`
+	Message string `json:"message" xml:"message"`
+}
+
+// Error é retornado por Decoder.JSON com o status HTTP adequado:
+// 400 (malformado), 413 (muito grande), 415 (media type) ou 422 (validação)
+type Error struct {
+	Status  int
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Option customiza o Decoder
+type Option func(*Decoder)
+
+// WithMaxBytes define o tamanho máximo do body
+func WithMaxBytes(n int64) Option {
+	return func(d *Decoder) {
+		d.maxBytes = n
+	}
+}
+
+// WithUnknownFields define se campos desconhecidos são aceitos (ignorados) ou rejeitados (padrão)
+func WithUnknownFields(allow bool) Option {
+	return func(d *Decoder) {
+		d.allowUnknownFields = allow
+	}
+}
+
+// Decoder decodifica e valida bodies JSON; é seguro para uso concorrente
+type Decoder struct {
+	maxBytes           int64
+	allowUnknownFields bool
+	validate           *validator.Validate
+}
+
+func New(opts ...Option) *Decoder {
+	d := &Decoder{
+		maxBytes: DefaultMaxBytes,
+		validate: validator.New(validator.WithRequiredStructEnabled()),
+	}
+	// Erros de validação usam o nome do campo no JSON
+	d.validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// JSON decodifica o body de r em dst (ponteiro para struct) e valida suas tags.
+// Sem Content-Type o body é tratado como JSON.
+func (d *Decoder) JSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != "application/json" {
+			return &Error{Status: http.StatusUnsupportedMediaType, Message: "unsupported media type", Err: fmt.Errorf("content type %q", contentType)}
+		}
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, d.maxBytes))
+	if !d.allowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+
+	// Um segundo valor (ou lixo) após o objeto torna o body inválido
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return decodeError(err)
+		}
+		return &Error{Status: http.StatusBadRequest, Message: "invalid request body", Err: errors.New("body must contain a single JSON object")}
+	}
+
+	return d.Validate(dst)
+}
+
+// Validate aplica as tags validate de v (ponteiro para struct) sem decodificar
+// nada, para entradas que não chegam como JSON (ex: gRPC). Falhas retornam
+// *Error com status 422 e os campos inválidos.
+func (d *Decoder) Validate(v interface{}) error {
+	if err := d.validate.Struct(v); err != nil {
+		var validationErrs validator.ValidationErrors
+		if !errors.As(err, &validationErrs) {
+			return fmt.Errorf("error validating request: %w", err)
+		}
+		return &Error{Status: http.StatusUnprocessableEntity, Message: "validation failed", Fields: fieldErrors(validationErrs), Err: err}
+	}
+
+	return nil
+}
+
+func decodeError(err error) error {
+	var (
+		maxBytesErr *http.MaxBytesError
+		typeErr     *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return &Error{Status: http.StatusRequestEntityTooLarge, Message: "request body too large", Err: err}
+	case errors.As(err, &typeErr):
+		return &Error{
+			Status:  http.StatusBadRequest,
+			Message: "invalid request body",
+			Fields:  []FieldError{{Field: typeErr.Field, Message: fmt.Sprintf("must be a %s", typeErr.Type)}},
+			Err:     err,
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json não expõe um tipo para campos desconhecidos
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return &Error{
+			Status:  http.StatusBadRequest,
+			Message: "invalid request body",
+			Fields:  []FieldError{{Field: field, Message: "unknown field"}},
+			Err:     err,
+		}
+	default:
+		return &Error{Status: http.StatusBadRequest, Message: "invalid request body", Err: err}
+	}
+}
+
+func fieldErrors(errs validator.ValidationErrors) []FieldError {
+	fields := make([]FieldError, 0, len(errs))
+	for _, err := range errs {
+		fields = append(fields, FieldError{Field: fieldPath(err), Message: fieldMessage(err)})
+	}
+	return fields
+}
+
+// fieldPath remove o nome da struct raiz do namespace (ex: WeatherRequest.cep -> cep)
+func fieldPath(err validator.FieldError) string {
+	_, path, ok := strings.Cut(err.Namespace(), ".")
+	if !ok {
+		return err.Field()
+	}
+	return path
+}
+
+func fieldMessage(err validator.FieldError) string {
+	switch err.Tag() {
+	case "required":
+		return "is required"
+	case "len":
+		if err.Kind() == reflect.String {
+			return fmt.Sprintf("must have exactly %s characters", err.Param())
+		}
+		return fmt.Sprintf("must have exactly %s items", err.Param())
+	case "min":
+		return fmt.Sprintf("must be at least %s", err.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s", err.Param())
+	case "numeric":
+		return "must contain only digits"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", err.Param())
+	case "unique":
+		return "must not contain duplicates"
+	default:
+		return fmt.Sprintf("failed %q validation", err.Tag())
+	}
+}
@@ -0,0 +1,162 @@
+package decode
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	CEP     string   `json:"cep" validate:"required,len=8"`
+	Tags    []string `json:"tags,omitempty" validate:"max=2,unique"`
+	Ignored string   `json:"-"`
+}
+
+func TestDecoderJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		contentType    string
+		body           string
+		expected       testRequest
+		expectedStatus int
+		expectedFields []FieldError
+	}{
+		{
+			name:        "valid body",
+			contentType: "application/json",
+			body:        `{"cep":"26140040"}`,
+			expected:    testRequest{CEP: "26140040"},
+		},
+		{
+			name:        "content type with charset",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"cep":"26140040"}`,
+			expected:    testRequest{CEP: "26140040"},
+		},
+		{
+			name:     "missing content type is treated as json",
+			body:     `{"cep":"26140040"}  ` + "\n",
+			expected: testRequest{CEP: "26140040"},
+		},
+		{
+			name:           "unsupported media type",
+			contentType:    "text/plain",
+			body:           `{"cep":"26140040"}`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "malformed json",
+			body:           `{"cep":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "empty body",
+			body:           ``,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "wrong field type",
+			body:           `{"cep":26140040}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []FieldError{{Field: "cep", Message: "must be a string"}},
+		},
+		{
+			name:           "unknown field rejected",
+			body:           `{"cep":"26140040","city":"Belford Roxo"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []FieldError{{Field: "city", Message: "unknown field"}},
+		},
+		{
+			name:     "unknown field allowed",
+			opts:     []Option{WithUnknownFields(true)},
+			body:     `{"cep":"26140040","city":"Belford Roxo"}`,
+			expected: testRequest{CEP: "26140040"},
+		},
+		{
+			name:           "trailing object",
+			body:           `{"cep":"26140040"}{"cep":"01310100"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "trailing object with unknown fields allowed",
+			opts:           []Option{WithUnknownFields(true)},
+			body:           `{"cep":"26140040"}{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "trailing garbage",
+			body:           `{"cep":"26140040"} garbage`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "body too large",
+			opts:           []Option{WithMaxBytes(16)},
+			body:           `{"cep":"26140040","tags":[]}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "missing required field",
+			body:           `{}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedFields: []FieldError{{Field: "cep", Message: "is required"}},
+		},
+		{
+			name:           "duplicate items",
+			body:           `{"cep":"26140040","tags":["a","a"]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedFields: []FieldError{{Field: "tags", Message: "must not contain duplicates"}},
+		},
+		{
+			name:           "multiple field errors",
+			body:           `{"cep":"123","tags":["a","b","c"]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedFields: []FieldError{
+				{Field: "cep", Message: "must have exactly 8 characters"},
+				{Field: "tags", Message: "must be at most 2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/weather", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			var dst testRequest
+			err := New(tt.opts...).JSON(httptest.NewRecorder(), req, &dst)
+
+			if tt.expectedStatus == 0 {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, dst)
+				return
+			}
+
+			var decodeErr *Error
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, tt.expectedStatus, decodeErr.Status)
+			assert.Equal(t, tt.expectedFields, decodeErr.Fields)
+		})
+	}
+}
+
+func TestDecoderValidate(t *testing.T) {
+	d := New()
+
+	assert.NoError(t, d.Validate(&testRequest{CEP: "26140040"}))
+
+	err := d.Validate(&testRequest{CEP: "123", Tags: []string{"a", "a"}})
+	var decodeErr *Error
+	require.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, decodeErr.Status)
+	assert.Equal(t, []FieldError{
+		{Field: "cep", Message: "must have exactly 8 characters"},
+		{Field: "tags", Message: "must not contain duplicates"},
+	}, decodeErr.Fields)
+}
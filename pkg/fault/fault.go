@@ -0,0 +1,348 @@
+// Package fault injeta falhas nas chamadas HTTP e gRPC a dependências (latência,
+// erros, status específicos e timeouts) para ensaiar cenários de caos. As
+// regras vêm da configuração e podem ser trocadas em execução pelo Handler
+// administrativo; cada falha injetada é registrada no span da chamada.
+package fault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrInjected é devolvido quando a regra simula uma falha de conexão (sem Status)
+var ErrInjected = errors.New("fault: injected connection failure")
+
+// Tipos de falha registrados no atributo fault.type do evento
+const (
+	TypeLatency = "latency"
+	TypeError   = "error"
+	TypeTimeout = "timeout"
+)
+
+// Rule descreve as falhas injetadas nas chamadas a uma dependência
+type Rule struct {
+	// Latency é somada a cada chamada
+	Latency time.Duration
+	// ErrorRate (0 a 1) é a fração de chamadas que falham: com Status a
+	// dependência "responde" esse status, sem ele a conexão falha
+	ErrorRate float64
+	Status    int
+	// TimeoutRate (0 a 1) é a fração de chamadas que ficam pendentes até o
+	// timeout do cliente (ou o cancelamento da requisição)
+	TimeoutRate float64
+}
+
+// ruleJSON é o formato de Rule na configuração e no endpoint administrativo
+type ruleJSON struct {
+	Latency     string  `json:"latency,omitempty"`
+	ErrorRate   float64 `json:"error_rate,omitempty"`
+	Status      int     `json:"status,omitempty"`
+	TimeoutRate float64 `json:"timeout_rate,omitempty"`
+}
+
+// MarshalJSON escreve a latência como duração legível (ex: "250ms")
+func (r Rule) MarshalJSON() ([]byte, error) {
+	out := ruleJSON{ErrorRate: r.ErrorRate, Status: r.Status, TimeoutRate: r.TimeoutRate}
+	if r.Latency > 0 {
+		out.Latency = r.Latency.String()
+	}
+	return json.Marshal(out)
+}
+
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	var in ruleJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*r = Rule{ErrorRate: in.ErrorRate, Status: in.Status, TimeoutRate: in.TimeoutRate}
+	if in.Latency != "" {
+		latency, err := time.ParseDuration(in.Latency)
+		if err != nil {
+			return fmt.Errorf("invalid latency %q: %w", in.Latency, err)
+		}
+		r.Latency = latency
+	}
+	return r.Validate()
+}
+
+// Validate confere taxas entre 0 e 1, latência não negativa e status HTTP válido
+func (r Rule) Validate() error {
+	switch {
+	case r.Latency < 0:
+		return fmt.Errorf("latency must not be negative")
+	case r.ErrorRate < 0 || r.ErrorRate > 1:
+		return fmt.Errorf("error_rate must be between 0 and 1")
+	case r.TimeoutRate < 0 || r.TimeoutRate > 1:
+		return fmt.Errorf("timeout_rate must be between 0 and 1")
+	case r.Status != 0 && (r.Status < 100 || r.Status > 599):
+		return fmt.Errorf("status must be a valid HTTP status")
+	}
+	return nil
+}
+
+// ParseRules lê as regras por dependência em JSON, no mesmo formato do Handler:
+// {"viacep":{"latency":"200ms","error_rate":0.1,"status":503}}. Vazio = sem
+// regras. Com dependencies, regras para outros nomes são rejeitadas.
+func ParseRules(value string, dependencies ...string) (map[string]Rule, error) {
+	rules := make(map[string]Rule)
+	if strings.TrimSpace(value) == "" {
+		return rules, nil
+	}
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, fmt.Errorf("invalid fault rules: %w", err)
+	}
+	for dependency := range rules {
+		if err := checkDependency(dependency, dependencies); err != nil {
+			return nil, fmt.Errorf("invalid fault rules: %w", err)
+		}
+	}
+	return rules, nil
+}
+
+// checkDependency rejeita nomes fora de dependencies (vazio = qualquer nome),
+// para que um erro de digitação não deixe a regra sem efeito
+func checkDependency(dependency string, dependencies []string) error {
+	if len(dependencies) == 0 || slices.Contains(dependencies, dependency) {
+		return nil
+	}
+	return fmt.Errorf("unknown dependency %q (expected %s)", dependency, strings.Join(dependencies, ", "))
+}
+
+// Option customiza o Injector
+type Option func(*Injector)
+
+// WithRules define as regras iniciais por dependência
+func WithRules(rules map[string]Rule) Option {
+	return func(i *Injector) {
+		for dependency, rule := range rules {
+			i.rules[dependency] = rule
+		}
+	}
+}
+
+// WithDependencies restringe as regras às dependências em que as falhas são
+// de fato injetadas; o Handler responde 404 para os demais nomes
+func WithDependencies(dependencies ...string) Option {
+	return func(i *Injector) {
+		i.dependencies = dependencies
+	}
+}
+
+// WithRandom substitui a fonte de aleatoriedade das taxas (valores em [0, 1))
+func WithRandom(random func() float64) Option {
+	return func(i *Injector) {
+		i.random = random
+	}
+}
+
+// Injector guarda as regras por dependência; é seguro para uso concorrente
+type Injector struct {
+	mu           sync.RWMutex
+	rules        map[string]Rule
+	dependencies []string
+	random       func() float64
+}
+
+func New(opts ...Option) *Injector {
+	i := &Injector{rules: make(map[string]Rule), random: rand.Float64}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+// Set substitui a regra da dependência
+func (i *Injector) Set(dependency string, rule Rule) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rules[dependency] = rule
+}
+
+// Clear remove a regra da dependência
+func (i *Injector) Clear(dependency string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.rules, dependency)
+}
+
+// Rules devolve uma cópia das regras ativas
+func (i *Injector) Rules() map[string]Rule {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	rules := make(map[string]Rule, len(i.rules))
+	for dependency, rule := range i.rules {
+		rules[dependency] = rule
+	}
+	return rules
+}
+
+func (i *Injector) rule(dependency string) (Rule, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	rule, ok := i.rules[dependency]
+	return rule, ok
+}
+
+// Wrap devolve um wrapper de transport para a dependência, compatível com as
+// opções WithTransportWrapper dos clientes. Deve ficar dentro da instrumentação
+// para que as falhas sejam registradas no span da chamada.
+func (i *Injector) Wrap(dependency string) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		return roundTripper{injector: i, dependency: dependency, next: next}
+	}
+}
+
+type roundTripper struct {
+	injector   *Injector
+	dependency string
+	next       http.RoundTripper
+}
+
+func (t roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	status, err := t.injector.inject(req.Context(), t.dependency)
+	switch {
+	case err != nil:
+		return nil, err
+	case status != 0:
+		return injectedResponse(req, status), nil
+	default:
+		return t.next.RoundTrip(req)
+	}
+}
+
+// inject aplica a regra da dependência antes da chamada: espera a latência e
+// sorteia timeout e erro. Devolve o status a responder (falha com Status) ou o
+// erro da chamada; sem nenhum dos dois a chamada segue para a dependência.
+func (i *Injector) inject(ctx context.Context, dependency string) (int, error) {
+	rule, ok := i.rule(dependency)
+	if !ok {
+		return 0, nil
+	}
+
+	if rule.Latency > 0 {
+		mark(ctx, dependency, TypeLatency, attribute.String("fault.latency", rule.Latency.String()))
+		if err := sleep(ctx, rule.Latency); err != nil {
+			return 0, err
+		}
+	}
+	if rule.TimeoutRate > 0 && i.random() < rule.TimeoutRate {
+		mark(ctx, dependency, TypeTimeout)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	if rule.ErrorRate > 0 && i.random() < rule.ErrorRate {
+		if rule.Status == 0 {
+			mark(ctx, dependency, TypeError)
+			return 0, ErrInjected
+		}
+		mark(ctx, dependency, TypeError, attribute.Int("fault.status", rule.Status))
+		return rule.Status, nil
+	}
+	return 0, nil
+}
+
+// mark registra a falha no span da chamada
+func mark(ctx context.Context, dependency, faultType string, attrs ...attribute.KeyValue) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Bool("fault.injected", true))
+	attrs = append([]attribute.KeyValue{
+		attribute.String("fault.dependency", dependency),
+		attribute.String("fault.type", faultType),
+	}, attrs...)
+	span.AddEvent("fault.injected", trace.WithAttributes(attrs...))
+}
+
+func injectedResponse(req *http.Request, status int) *http.Response {
+	body := []byte(`{"message":"injected fault"}`)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}, "X-Fault-Injected": []string{"true"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Handler é o endpoint administrativo, montado com http.StripPrefix (ex: em
+// /admin/faults): GET / lista as regras, PUT /{dependency} define e DELETE
+// /{dependency} remove; dependências fora de WithDependencies recebem 404.
+// Não há autenticação própria: habilite apenas fora de produção.
+func (i *Injector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dependency := strings.Trim(r.URL.Path, "/")
+		if dependency != "" {
+			if err := checkDependency(dependency, i.dependencies); err != nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"message": err.Error()})
+				return
+			}
+		}
+
+		switch {
+		case r.Method == http.MethodGet && dependency == "":
+			writeJSON(w, http.StatusOK, i.Rules())
+		case r.Method == http.MethodPut && dependency != "":
+			var rule Rule
+			if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&rule); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+				return
+			}
+			i.Set(dependency, rule)
+			writeJSON(w, http.StatusOK, rule)
+		case r.Method == http.MethodDelete && dependency != "":
+			i.Clear(dependency)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"message": "method not allowed"})
+		}
+	})
+}
+
+// Dependencies lista as dependências com regra, em ordem alfabética
+func (i *Injector) Dependencies() []string {
+	rules := i.Rules()
+	dependencies := make([]string, 0, len(rules))
+	for dependency := range rules {
+		dependencies = append(dependencies, dependency)
+	}
+	sort.Strings(dependencies)
+	return dependencies
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
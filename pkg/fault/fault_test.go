@@ -0,0 +1,252 @@
+package fault
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestInjectorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name           string
+		rules          map[string]Rule
+		random         float64
+		timeout        time.Duration
+		expectedStatus int
+		expectedErr    error
+		expectedFault  string
+		minDuration    time.Duration
+	}{
+		{name: "no rule", expectedStatus: http.StatusOK},
+		{name: "rule for another dependency", rules: map[string]Rule{"weatherapi": {ErrorRate: 1}}, expectedStatus: http.StatusOK},
+		{
+			name:           "latency",
+			rules:          map[string]Rule{"viacep": {Latency: 50 * time.Millisecond}},
+			expectedStatus: http.StatusOK,
+			expectedFault:  TypeLatency,
+			minDuration:    50 * time.Millisecond,
+		},
+		{
+			name:           "status",
+			rules:          map[string]Rule{"viacep": {ErrorRate: 0.5, Status: http.StatusServiceUnavailable}},
+			random:         0.4,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedFault:  TypeError,
+		},
+		{
+			name:           "error rate not drawn",
+			rules:          map[string]Rule{"viacep": {ErrorRate: 0.5, Status: http.StatusServiceUnavailable}},
+			random:         0.6,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:          "connection failure",
+			rules:         map[string]Rule{"viacep": {ErrorRate: 1}},
+			expectedErr:   ErrInjected,
+			expectedFault: TypeError,
+		},
+		{
+			name:          "timeout",
+			rules:         map[string]Rule{"viacep": {TimeoutRate: 1}},
+			timeout:       30 * time.Millisecond,
+			expectedErr:   context.DeadlineExceeded,
+			expectedFault: TypeTimeout,
+			minDuration:   30 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{}`))
+			}))
+			defer upstream.Close()
+
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			injector := New(WithRules(tt.rules), WithRandom(func() float64 { return tt.random }))
+			client := &http.Client{
+				Transport: otelhttp.NewTransport(injector.Wrap("viacep")(http.DefaultTransport), otelhttp.WithTracerProvider(provider)),
+				Timeout:   tt.timeout,
+			}
+
+			start := time.Now()
+			resp, err := client.Get(upstream.URL)
+			elapsed := time.Since(start)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				resp.Body.Close()
+				assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			}
+			assert.GreaterOrEqual(t, elapsed, tt.minDuration)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			var faults []string
+			for _, event := range spans[0].Events() {
+				if event.Name != "fault.injected" {
+					continue
+				}
+				for _, attr := range event.Attributes {
+					if attr.Key == "fault.type" {
+						faults = append(faults, attr.Value.AsString())
+					}
+				}
+			}
+			if tt.expectedFault == "" {
+				assert.Empty(t, faults)
+				return
+			}
+			assert.Equal(t, []string{tt.expectedFault}, faults)
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        string
+		dependencies []string
+		expected     map[string]Rule
+		expectedErr  string
+	}{
+		{name: "empty", value: "", expected: map[string]Rule{}},
+		{
+			name:  "rules",
+			value: `{"viacep":{"latency":"200ms","error_rate":0.1,"status":503},"weatherapi":{"timeout_rate":0.5}}`,
+			expected: map[string]Rule{
+				"viacep":     {Latency: 200 * time.Millisecond, ErrorRate: 0.1, Status: 503},
+				"weatherapi": {TimeoutRate: 0.5},
+			},
+		},
+		{name: "invalid json", value: `{`, expectedErr: "invalid fault rules"},
+		{name: "invalid latency", value: `{"viacep":{"latency":"soon"}}`, expectedErr: "invalid latency"},
+		{name: "rate above one", value: `{"viacep":{"error_rate":2}}`, expectedErr: "error_rate must be between 0 and 1"},
+		{name: "invalid status", value: `{"viacep":{"error_rate":1,"status":42}}`, expectedErr: "status must be a valid HTTP status"},
+		{
+			name:         "known dependency",
+			value:        `{"viacep":{"error_rate":1}}`,
+			dependencies: []string{"viacep", "weatherapi"},
+			expected:     map[string]Rule{"viacep": {ErrorRate: 1}},
+		},
+		{
+			name:         "unknown dependency",
+			value:        `{"viacpe":{"error_rate":1}}`,
+			dependencies: []string{"viacep", "weatherapi"},
+			expectedErr:  `unknown dependency "viacpe" (expected viacep, weatherapi)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := ParseRules(tt.value, tt.dependencies...)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rules)
+		})
+	}
+}
+
+func TestInjectorHandler(t *testing.T) {
+	injector := New(WithRules(map[string]Rule{"viacep": {Latency: time.Second}}), WithDependencies("viacep", "weatherapi"))
+	handler := http.StripPrefix("/admin/faults", injector.Handler())
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := do("GET", "/admin/faults", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"viacep":{"latency":"1s"}}`, rec.Body.String())
+
+	rec = do("PUT", "/admin/faults/weatherapi", `{"error_rate":1,"status":503}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, Rule{ErrorRate: 1, Status: 503}, injector.Rules()["weatherapi"])
+
+	rec = do("PUT", "/admin/faults/weatherapi", `{"error_rate":-1}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = do("PUT", "/admin/faults/weather", `{"error_rate":1}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"message":"unknown dependency \"weather\" (expected viacep, weatherapi)"}`, rec.Body.String())
+	assert.NotContains(t, injector.Rules(), "weather")
+
+	rec = do("DELETE", "/admin/faults/viacep", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, []string{"weatherapi"}, injector.Dependencies())
+
+	rec = do("POST", "/admin/faults", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	body, _ := io.ReadAll(rec.Body)
+	assert.Contains(t, string(body), "method not allowed")
+}
+
+func TestInjectorUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name          string
+		rules         map[string]Rule
+		timeout       time.Duration
+		expectedCode  codes.Code
+		expectInvoker bool
+	}{
+		{name: "no rule", expectedCode: codes.OK, expectInvoker: true},
+		{name: "latency", rules: map[string]Rule{"service-b": {Latency: 10 * time.Millisecond}}, expectedCode: codes.OK, expectInvoker: true},
+		{name: "status", rules: map[string]Rule{"service-b": {ErrorRate: 1, Status: http.StatusNotFound}}, expectedCode: codes.NotFound},
+		{name: "server error", rules: map[string]Rule{"service-b": {ErrorRate: 1, Status: http.StatusInternalServerError}}, expectedCode: codes.Internal},
+		{name: "connection failure", rules: map[string]Rule{"service-b": {ErrorRate: 1}}, expectedCode: codes.Unavailable},
+		{name: "timeout", rules: map[string]Rule{"service-b": {TimeoutRate: 1}}, timeout: 20 * time.Millisecond, expectedCode: codes.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			ctx, span := provider.Tracer("test").Start(context.Background(), "call-service-b")
+
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			invoked := false
+			invoker := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+				invoked = true
+				return nil
+			}
+			interceptor := New(WithRules(tt.rules), WithRandom(func() float64 { return 0 })).UnaryClientInterceptor("service-b")
+			err := interceptor(ctx, "/weather.v1.WeatherService/GetWeather", nil, nil, nil, invoker)
+			span.End()
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.expectInvoker, invoked)
+			injected := false
+			for _, event := range recorder.Ended()[0].Events() {
+				injected = injected || event.Name == "fault.injected"
+			}
+			assert.Equal(t, len(tt.rules) > 0, injected)
+		})
+	}
+}
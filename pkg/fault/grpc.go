@@ -0,0 +1,60 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor aplica as mesmas regras de Wrap às chamadas gRPC da
+// dependência. O Status da regra vira o código gRPC equivalente (ex: 404 ->
+// NOT_FOUND, 503 -> UNAVAILABLE) e a falha de conexão, UNAVAILABLE.
+func (i *Injector) UnaryClientInterceptor(dependency string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		httpStatus, err := i.inject(ctx, dependency)
+		switch {
+		case errors.Is(err, ErrInjected):
+			return status.Error(codes.Unavailable, err.Error())
+		case err != nil:
+			return status.FromContextError(err).Err()
+		case httpStatus != 0:
+			return status.Errorf(grpcCode(httpStatus), "injected fault: status %d", httpStatus)
+		default:
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+}
+
+// grpcCode converte o status HTTP da regra no código gRPC de mesmo sentido
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if httpStatus >= http.StatusInternalServerError {
+		return codes.Internal
+	}
+	return codes.Unknown
+}
@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HTTPCheck considera a dependência disponível quando a URL responde com status abaixo de 500.
+// Serve tanto para endpoints de health quanto para checar apenas a alcançabilidade de um provedor.
+func HTTPCheck(client *http.Client, target string) CheckFunc {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return fmt.Errorf("error creating request: %w", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("error calling %s: %w", req.URL.Host, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// GRPCCheck consulta o serviço de health do gRPC (grpc.health.v1) pela conexão
+// conn; a dependência está disponível quando service responde SERVING
+// (service vazio = o servidor como um todo)
+func GRPCCheck(conn grpc.ClientConnInterface, service string) CheckFunc {
+	client := healthpb.NewHealthClient(conn)
+	return func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return fmt.Errorf("error checking gRPC health: %w", err)
+		}
+		if status := resp.GetStatus(); status != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("gRPC health status %s", status)
+		}
+		return nil
+	}
+}
+
+// TCPCheck verifica se é possível abrir uma conexão TCP com o host da URL
+func TCPCheck(target string) CheckFunc {
+	return func(ctx context.Context) error {
+		address, err := hostPort(target)
+		if err != nil {
+			return err
+		}
+
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return fmt.Errorf("error connecting to %s: %w", address, err)
+		}
+		return conn.Close()
+	}
+}
+
+func hostPort(target string) (string, error) {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid URL: %q", target)
+	}
+
+	if u.Port() != "" {
+		return u.Host, nil
+	}
+	if u.Scheme == "https" {
+		return net.JoinHostPort(u.Hostname(), "443"), nil
+	}
+	return net.JoinHostPort(u.Hostname(), "80"), nil
+}
@@ -0,0 +1,184 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Status possíveis de um check e do relatório
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckFunc verifica uma dependência; retornar erro marca a dependência como indisponível
+type CheckFunc func(ctx context.Context) error
+
+// Result é o resultado de um check individual
+type Result struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report é o relatório detalhado retornado pelo endpoint de readiness
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker executa os checks registrados e mantém os resultados em cache por ttl,
+// evitando que probes frequentes sobrecarreguem as dependências. Chamadas
+// simultâneas aguardam a mesma execução de cada check, sem segurar o lock.
+type Checker struct {
+	ttl     time.Duration
+	timeout time.Duration
+	now     func() time.Time
+
+	mu       sync.Mutex
+	checks   []check
+	cache    map[string]Result
+	inflight map[string]*flight
+}
+
+// flight é uma execução de check em andamento; result vale após done fechar
+type flight struct {
+	done   chan struct{}
+	result Result
+}
+
+// NewChecker cria um Checker com o tempo de cache e o timeout de cada check
+func NewChecker(ttl, timeout time.Duration) *Checker {
+	return &Checker{
+		ttl:      ttl,
+		timeout:  timeout,
+		now:      time.Now,
+		cache:    make(map[string]Result),
+		inflight: make(map[string]*flight),
+	}
+}
+
+// Register adiciona um check de dependência à readiness
+func (c *Checker) Register(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// Check executa em paralelo os checks cujo resultado em cache expirou e monta o
+// relatório. Se ctx for cancelado antes, os checks pendentes aparecem como
+// indisponíveis apenas neste relatório: eles continuam e o cache recebe o
+// resultado real.
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.Lock()
+	now := c.now()
+	results := make(map[string]Result, len(c.checks))
+	pending := make(map[string]*flight)
+	for _, chk := range c.checks {
+		if cached, ok := c.cache[chk.name]; ok && now.Sub(cached.CheckedAt) < c.ttl {
+			results[chk.name] = cached
+			continue
+		}
+
+		f, ok := c.inflight[chk.name]
+		if !ok {
+			f = &flight{done: make(chan struct{})}
+			c.inflight[chk.name] = f
+			go c.run(ctx, chk, f)
+		}
+		pending[chk.name] = f
+	}
+	c.mu.Unlock()
+
+	for name, f := range pending {
+		select {
+		case <-f.done:
+			results[name] = f.result
+		case <-ctx.Done():
+			results[name] = Result{
+				Status:    StatusDown,
+				Error:     ctx.Err().Error(),
+				Duration:  c.now().Sub(now).String(),
+				CheckedAt: now,
+			}
+		}
+	}
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, result := range results {
+		if result.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+// run executa o check desvinculado do cancelamento de quem o iniciou, já que
+// outras chamadas aguardam o mesmo resultado, e o guarda no cache
+func (c *Checker) run(ctx context.Context, chk check, f *flight) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
+	defer cancel()
+
+	start := c.now()
+	err := chk.fn(ctx)
+
+	result := Result{
+		Status:    StatusUp,
+		Duration:  c.now().Sub(start).String(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	c.mu.Lock()
+	c.cache[chk.name] = result
+	delete(c.inflight, chk.name)
+	c.mu.Unlock()
+
+	f.result = result
+	close(f.done)
+}
+
+// LivenessHandler indica apenas que o processo está respondendo, sem consultar dependências
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, http.StatusOK, Report{Status: StatusUp})
+	}
+}
+
+// ReadinessHandler retorna 200 quando todas as dependências estão disponíveis e 503 caso contrário
+func (c *Checker) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := c.Check(r.Context())
+
+		statusCode := http.StatusOK
+		if report.Status != StatusUp {
+			statusCode = http.StatusServiceUnavailable
+		}
+		writeReport(w, statusCode, report)
+	}
+}
+
+func writeReport(w http.ResponseWriter, statusCode int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Printf("Error writing health report: %v", err)
+	}
+}
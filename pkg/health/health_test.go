@@ -0,0 +1,229 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestCheckerReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		checks         map[string]CheckFunc
+		expectedStatus int
+		expectedReport string
+	}{
+		{
+			name:           "no dependencies",
+			checks:         nil,
+			expectedStatus: http.StatusOK,
+			expectedReport: StatusUp,
+		},
+		{
+			name: "all dependencies up",
+			checks: map[string]CheckFunc{
+				"viacep":     func(context.Context) error { return nil },
+				"weatherapi": func(context.Context) error { return nil },
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: StatusUp,
+		},
+		{
+			name: "one dependency down",
+			checks: map[string]CheckFunc{
+				"viacep":     func(context.Context) error { return nil },
+				"weatherapi": func(context.Context) error { return errors.New("connection refused") },
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedReport: StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(time.Minute, time.Second)
+			for name, fn := range tt.checks {
+				checker.Register(name, fn)
+			}
+
+			rec := httptest.NewRecorder()
+			checker.ReadinessHandler()(rec, httptest.NewRequest("GET", "/readyz", nil))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var report Report
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+			assert.Equal(t, tt.expectedReport, report.Status)
+			assert.Len(t, report.Checks, len(tt.checks))
+			for name, result := range report.Checks {
+				if tt.checks[name](context.Background()) != nil {
+					assert.Equal(t, StatusDown, result.Status)
+					assert.NotEmpty(t, result.Error)
+				} else {
+					assert.Equal(t, StatusUp, result.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckerCachesResults(t *testing.T) {
+	var calls atomic.Int32
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	checker := NewChecker(10*time.Second, time.Second)
+	checker.now = func() time.Time { return now }
+	checker.Register("service-b", func(context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	checker.Check(context.Background())
+	now = now.Add(5 * time.Second)
+	checker.Check(context.Background())
+	assert.Equal(t, int32(1), calls.Load(), "result should be served from cache")
+
+	now = now.Add(10 * time.Second)
+	checker.Check(context.Background())
+	assert.Equal(t, int32(2), calls.Load(), "expired result should be checked again")
+}
+
+func TestCheckerTimeout(t *testing.T) {
+	checker := NewChecker(time.Minute, 10*time.Millisecond)
+	checker.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checker.Check(context.Background())
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Contains(t, report.Checks["slow"].Error, "deadline exceeded")
+}
+
+func TestCheckerSharesInflightChecks(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	checker := NewChecker(time.Minute, time.Second)
+	checker.Register("slow", func(context.Context) error {
+		calls.Add(1)
+		<-release
+		return nil
+	})
+
+	// Chamadas simultâneas aguardam a mesma execução do check
+	reports := make(chan Report, 2)
+	for i := 0; i < 2; i++ {
+		go func() { reports <- checker.Check(context.Background()) }()
+	}
+
+	// Um check em andamento não bloqueia quem desiste antes do fim
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	report := checker.Check(ctx)
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Contains(t, report.Checks["slow"].Error, "deadline exceeded")
+
+	close(release)
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, StatusUp, (<-reports).Status)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCheckerDoesNotCacheCallerCancellation(t *testing.T) {
+	var calls atomic.Int32
+	checker := NewChecker(time.Minute, time.Second)
+	checker.Register("service-b", func(ctx context.Context) error {
+		calls.Add(1)
+		return ctx.Err()
+	})
+
+	// O cancelamento do chamador não chega ao check nem ao cache
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	checker.Check(ctx)
+
+	require.Eventually(t, func() bool {
+		return checker.Check(context.Background()).Status == StatusUp
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load(), "the real result should be cached")
+}
+
+func TestLivenessHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	LivenessHandler()(rec, httptest.NewRequest("GET", "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"up"}`, rec.Body.String())
+}
+
+func TestHTTPCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		expectErr  bool
+	}{
+		{"ok", http.StatusOK, false},
+		{"not found still reachable", http.StatusNotFound, false},
+		{"server error", http.StatusBadGateway, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			err := HTTPCheck(server.Client(), server.URL)(context.Background())
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTCPCheck(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	assert.NoError(t, TCPCheck(server.URL+"/api/v2/spans")(context.Background()))
+
+	server.Close()
+	assert.Error(t, TCPCheck(server.URL)(context.Background()))
+	assert.Error(t, TCPCheck("not a url")(context.Background()))
+}
+
+func TestGRPCCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	healthServer := grpchealth.NewServer()
+	healthServer.SetServingStatus("weather.v1.WeatherService", healthpb.HealthCheckResponse_NOT_SERVING)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	assert.NoError(t, GRPCCheck(conn, "")(context.Background()))
+	assert.EqualError(t, GRPCCheck(conn, "weather.v1.WeatherService")(context.Background()), "gRPC health status NOT_SERVING")
+	assert.Error(t, GRPCCheck(conn, "unknown.Service")(context.Background()))
+}
@@ -0,0 +1,318 @@
+// Package loadtest dispara requisições POST /weather contra o Service A com
+// taxa, concorrência e duração configuráveis, percorrendo os CEPs de um corpus, e
+// resume as latências (percentis) e os resultados por status e mensagem.
+package loadtest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/stubs"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Corpora embutidos, selecionados por nome em LoadCorpus
+const (
+	// CorpusValid usa os CEPs das fixtures de pkg/stubs (respondem 200 no modo offline)
+	CorpusValid = "valid"
+	// CorpusInvalid usa CEPs mal formatados (422 no Service A)
+	CorpusInvalid = "invalid"
+	// CorpusMixed combina os dois, com um CEP inexistente (404)
+	CorpusMixed = "mixed"
+)
+
+var invalidZipcodes = []string{"1234567", "123456789", "abcdefgh", "26140-04"}
+
+// LoadCorpus devolve um corpus embutido pelo nome ou, caso contrário, lê o
+// arquivo informado (um CEP por linha; linhas vazias e iniciadas por # são ignoradas)
+func LoadCorpus(name string) ([]string, error) {
+	switch name {
+	case CorpusValid, CorpusInvalid, CorpusMixed:
+		return builtinCorpus(name)
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("error opening corpus: %w", err)
+	}
+	defer file.Close()
+	return ReadZipcodes(file)
+}
+
+func builtinCorpus(name string) ([]string, error) {
+	fixtures, err := stubs.LoadFixtures(stubs.DefaultFixtures)
+	if err != nil {
+		return nil, err
+	}
+	valid := make([]string, 0, len(fixtures.Zipcodes))
+	for zipcode := range fixtures.Zipcodes {
+		valid = append(valid, zipcode)
+	}
+	sort.Strings(valid)
+
+	switch name {
+	case CorpusValid:
+		return valid, nil
+	case CorpusInvalid:
+		return invalidZipcodes, nil
+	default:
+		return append(append(valid, invalidZipcodes...), "99999999"), nil
+	}
+}
+
+// ReadZipcodes lê um CEP por linha, ignorando linhas vazias e comentários (#)
+func ReadZipcodes(r io.Reader) ([]string, error) {
+	var zipcodes []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		zipcodes = append(zipcodes, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading zipcodes: %w", err)
+	}
+	if len(zipcodes) == 0 {
+		return nil, errors.New("corpus has no zipcodes")
+	}
+	return zipcodes, nil
+}
+
+// Option customiza o Runner
+type Option func(*Runner)
+
+// WithRate limita as requisições por segundo (0 = o máximo que a concorrência permitir)
+func WithRate(rps float64) Option {
+	return func(r *Runner) {
+		r.rate = rps
+	}
+}
+
+// WithConcurrency define o número de requisições simultâneas (padrão 10)
+func WithConcurrency(concurrency int) Option {
+	return func(r *Runner) {
+		if concurrency > 0 {
+			r.concurrency = concurrency
+		}
+	}
+}
+
+// WithDuration define por quanto tempo novas requisições são disparadas (padrão 10s)
+func WithDuration(duration time.Duration) Option {
+	return func(r *Runner) {
+		r.duration = duration
+	}
+}
+
+// WithRequests encerra após o número informado de requisições, antes da duração (0 = sem limite)
+func WithRequests(requests int) Option {
+	return func(r *Runner) {
+		r.requests = requests
+	}
+}
+
+// WithZipcodes define o corpus, percorrido em ordem e de forma circular
+func WithZipcodes(zipcodes []string) Option {
+	return func(r *Runner) {
+		r.zipcodes = zipcodes
+	}
+}
+
+// WithHeader adiciona um header a todas as requisições (ex: X-API-Key)
+func WithHeader(name, value string) Option {
+	return func(r *Runner) {
+		r.header.Add(name, value)
+	}
+}
+
+// WithTimeout limita cada requisição (padrão 10s)
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *Runner) {
+		r.timeout = timeout
+	}
+}
+
+// WithTransport substitui o transport HTTP, que é sempre instrumentado com otelhttp
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Runner) {
+		r.transport = transport
+	}
+}
+
+// Runner dispara a carga. Cada requisição abre o span loadtest.request com o
+// TracerProvider global: sem provider configurado os spans são descartados.
+type Runner struct {
+	target      string
+	rate        float64
+	concurrency int
+	duration    time.Duration
+	requests    int
+	zipcodes    []string
+	header      http.Header
+	timeout     time.Duration
+	transport   http.RoundTripper
+	client      *http.Client
+	tracer      trace.Tracer
+}
+
+// New cria o Runner para a URL base do Service A (ex: http://localhost:8080)
+func New(target string, opts ...Option) *Runner {
+	r := &Runner{
+		target:      strings.TrimRight(target, "/"),
+		concurrency: 10,
+		duration:    10 * time.Second,
+		header:      make(http.Header),
+		timeout:     10 * time.Second,
+		transport:   http.DefaultTransport,
+		tracer:      otel.Tracer("loadtest"),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if len(r.zipcodes) == 0 {
+		r.zipcodes, _ = builtinCorpus(CorpusValid)
+	}
+	r.client = &http.Client{
+		Timeout:   r.timeout,
+		Transport: otelhttp.NewTransport(r.transport),
+	}
+	return r
+}
+
+// result é o desfecho de uma requisição
+type result struct {
+	latency time.Duration
+	outcome string
+	ok      bool
+}
+
+// Run dispara as requisições até a duração, o limite de requisições ou o
+// cancelamento de ctx, aguarda as pendentes e devolve o relatório
+func (r *Runner) Run(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, r.duration)
+	defer cancel()
+
+	jobs := make(chan string)
+	results := make(chan result)
+
+	var workers sync.WaitGroup
+	for i := 0; i < r.concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for zipcode := range jobs {
+				// As pendentes terminam mesmo após a duração, limitadas pelo timeout
+				results <- r.do(context.WithoutCancel(ctx), zipcode)
+			}
+		}()
+	}
+
+	start := time.Now()
+	go func() {
+		r.schedule(ctx, jobs)
+		close(jobs)
+		workers.Wait()
+		close(results)
+	}()
+
+	collector := newCollector()
+	for res := range results {
+		collector.add(res)
+	}
+	return collector.report(time.Since(start), r.rate, r.concurrency)
+}
+
+// schedule entrega os CEPs aos workers no ritmo configurado. Quando todos os
+// workers estão ocupados o envio bloqueia, e a taxa efetiva fica abaixo da pedida.
+func (r *Runner) schedule(ctx context.Context, jobs chan<- string) {
+	var tick <-chan time.Time
+	if r.rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / r.rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for sent := 0; r.requests == 0 || sent < r.requests; sent++ {
+		if tick != nil && sent > 0 {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case jobs <- r.zipcodes[sent%len(r.zipcodes)]:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// do envia uma requisição e classifica o desfecho por status e mensagem
+func (r *Runner) do(ctx context.Context, zipcode string) result {
+	ctx, span := r.tracer.Start(ctx, "loadtest.request", trace.WithAttributes(attribute.String("loadtest.zipcode", zipcode)))
+	defer span.End()
+
+	body, _ := json.Marshal(map[string]string{"cep": zipcode})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.target+"/weather", bytes.NewReader(body))
+	if err != nil {
+		return result{outcome: "error: " + err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+
+	start := time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		latency := time.Since(start)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return result{latency: latency, outcome: "error: " + transportError(err)}
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	latency := time.Since(start)
+
+	outcome := fmt.Sprintf("%d", resp.StatusCode)
+	ok := resp.StatusCode < 400
+	if !ok {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &errResp) == nil && errResp.Message != "" {
+			outcome += " " + errResp.Message
+		}
+		span.SetStatus(codes.Error, outcome)
+	}
+	return result{latency: latency, outcome: outcome, ok: ok}
+}
+
+// transportError remove a URL de *url.Error, para agrupar os erros iguais
+func transportError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return err.Error()
+}
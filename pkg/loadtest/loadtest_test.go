@@ -0,0 +1,172 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// weatherServer responde como o Service A: 200 para 26140040, 404 para
+// 99999999 e 422 para o resto
+func weatherServer(t *testing.T, requests *atomic.Int64) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, "/weather", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("X-API-Key"))
+
+		var req struct {
+			CEP string `json:"cep"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch req.CEP {
+		case "26140040":
+			io.WriteString(w, `{"city":"Belford Roxo","temp_C":31.2,"temp_F":88.2,"temp_K":304.2}`)
+		case "99999999":
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"message":"can not find zipcode"}`)
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			io.WriteString(w, `{"message":"invalid zipcode"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRunnerOutcomes(t *testing.T) {
+	var requests atomic.Int64
+	server := weatherServer(t, &requests)
+
+	report := New(server.URL,
+		WithZipcodes([]string{"26140040", "26140040", "99999999", "1234"}),
+		WithRequests(8),
+		WithConcurrency(3),
+		WithHeader("X-API-Key", "secret"),
+	).Run(context.Background())
+
+	assert.EqualValues(t, 8, requests.Load())
+	assert.Equal(t, 8, report.Requests)
+	assert.Equal(t, 4, report.Failures)
+	assert.Equal(t, []Outcome{
+		{Name: "200", Count: 4},
+		{Name: "404 can not find zipcode", Count: 2},
+		{Name: "422 invalid zipcode", Count: 2},
+	}, report.Outcomes)
+	assert.Positive(t, report.Latencies.Max)
+	assert.LessOrEqual(t, report.Latencies.P50, report.Latencies.P99)
+}
+
+func TestRunnerRateAndDuration(t *testing.T) {
+	var requests atomic.Int64
+	server := weatherServer(t, &requests)
+
+	report := New(server.URL,
+		WithZipcodes([]string{"26140040"}),
+		WithRate(50),
+		WithDuration(200*time.Millisecond),
+		WithHeader("X-API-Key", "secret"),
+	).Run(context.Background())
+
+	// 50/s por 200ms: cerca de 10 requisições, a primeira sem espera
+	assert.InDelta(t, 10, report.Requests, 3)
+	assert.Zero(t, report.Failures)
+}
+
+func TestRunnerTransportErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	report := New(server.URL, WithRequests(3), WithConcurrency(1)).Run(context.Background())
+
+	require.Len(t, report.Outcomes, 1)
+	assert.Equal(t, 3, report.Outcomes[0].Count)
+	assert.True(t, strings.HasPrefix(report.Outcomes[0].Name, "error: "), report.Outcomes[0].Name)
+	assert.NotContains(t, report.Outcomes[0].Name, server.URL)
+}
+
+func TestSummarize(t *testing.T) {
+	latencies := make([]time.Duration, 0, 100)
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	assert.Equal(t, Latencies{
+		Min:  time.Millisecond,
+		Mean: 50500 * time.Microsecond,
+		P50:  50 * time.Millisecond,
+		P90:  90 * time.Millisecond,
+		P95:  95 * time.Millisecond,
+		P99:  99 * time.Millisecond,
+		Max:  100 * time.Millisecond,
+	}, summarize(latencies))
+	assert.Equal(t, Latencies{}, summarize(nil))
+}
+
+func TestLoadCorpus(t *testing.T) {
+	file := t.TempDir() + "/ceps.txt"
+	require.NoError(t, os.WriteFile(file, []byte("# centro\n01310100\n\n 26140040 \n"), 0o600))
+	empty := t.TempDir() + "/empty.txt"
+	require.NoError(t, os.WriteFile(empty, []byte("# nada\n"), 0o600))
+
+	tests := []struct {
+		name        string
+		corpus      string
+		expected    []string
+		expectedLen int
+		expectedErr string
+	}{
+		{name: "file", corpus: file, expected: []string{"01310100", "26140040"}},
+		{name: "valid", corpus: CorpusValid, expectedLen: 5},
+		{name: "invalid", corpus: CorpusInvalid, expected: invalidZipcodes},
+		{name: "mixed", corpus: CorpusMixed, expectedLen: 10},
+		{name: "empty file", corpus: empty, expectedErr: "corpus has no zipcodes"},
+		{name: "missing file", corpus: "missing.txt", expectedErr: "error opening corpus"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zipcodes, err := LoadCorpus(tt.corpus)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expected != nil {
+				assert.Equal(t, tt.expected, zipcodes)
+			} else {
+				assert.Len(t, zipcodes, tt.expectedLen)
+			}
+		})
+	}
+}
+
+func TestReportWrite(t *testing.T) {
+	report := &Report{
+		Requests:    4,
+		Failures:    1,
+		Elapsed:     time.Second,
+		Concurrency: 2,
+		Throughput:  4,
+		Latencies:   Latencies{P50: 12 * time.Millisecond},
+		Outcomes:    []Outcome{{Name: "200", Count: 3}, {Name: "422 invalid zipcode", Count: 1}},
+	}
+
+	var out strings.Builder
+	require.NoError(t, report.Write(&out))
+	assert.Contains(t, out.String(), "4 (1 failed)")
+	assert.Contains(t, out.String(), "unlimited requested, 4.0/s achieved (concurrency 2)")
+	assert.Contains(t, out.String(), "12ms")
+	assert.Contains(t, out.String(), "422 invalid zipcode  1      25.0")
+}
@@ -0,0 +1,146 @@
+package loadtest
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Percentis reportados em Latencies
+var percentiles = []float64{50, 90, 95, 99}
+
+// Latencies resume a distribuição das latências
+type Latencies struct {
+	Min  time.Duration
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P95  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+// Outcome conta as requisições com o mesmo desfecho ("200", "404 can not
+// find zipcode", "error: connection refused")
+type Outcome struct {
+	Name  string
+	Count int
+}
+
+// Report é o resultado de uma execução
+type Report struct {
+	Requests    int
+	Failures    int
+	Elapsed     time.Duration
+	Rate        float64
+	Concurrency int
+	// Throughput são as requisições concluídas por segundo
+	Throughput float64
+	Latencies  Latencies
+	// Outcomes vem ordenado do desfecho mais frequente ao menos frequente
+	Outcomes []Outcome
+}
+
+type collector struct {
+	latencies []time.Duration
+	outcomes  map[string]int
+	failures  int
+}
+
+func newCollector() *collector {
+	return &collector{outcomes: make(map[string]int)}
+}
+
+func (c *collector) add(res result) {
+	c.latencies = append(c.latencies, res.latency)
+	c.outcomes[res.outcome]++
+	if !res.ok {
+		c.failures++
+	}
+}
+
+func (c *collector) report(elapsed time.Duration, rate float64, concurrency int) *Report {
+	report := &Report{
+		Requests:    len(c.latencies),
+		Failures:    c.failures,
+		Elapsed:     elapsed,
+		Rate:        rate,
+		Concurrency: concurrency,
+		Latencies:   summarize(c.latencies),
+	}
+	if elapsed > 0 {
+		report.Throughput = float64(report.Requests) / elapsed.Seconds()
+	}
+	for name, count := range c.outcomes {
+		report.Outcomes = append(report.Outcomes, Outcome{Name: name, Count: count})
+	}
+	sort.Slice(report.Outcomes, func(i, j int) bool {
+		if report.Outcomes[i].Count != report.Outcomes[j].Count {
+			return report.Outcomes[i].Count > report.Outcomes[j].Count
+		}
+		return report.Outcomes[i].Name < report.Outcomes[j].Name
+	})
+	return report
+}
+
+// summarize calcula os percentis pelo método nearest-rank
+func summarize(latencies []time.Duration) Latencies {
+	if len(latencies) == 0 {
+		return Latencies{}
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, latency := range sorted {
+		total += latency
+	}
+	values := make([]time.Duration, len(percentiles))
+	for i, p := range percentiles {
+		rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+		values[i] = sorted[max(rank-1, 0)]
+	}
+
+	return Latencies{
+		Min:  sorted[0],
+		Mean: total / time.Duration(len(sorted)),
+		P50:  values[0],
+		P90:  values[1],
+		P95:  values[2],
+		P99:  values[3],
+		Max:  sorted[len(sorted)-1],
+	}
+}
+
+// Write imprime o relatório em texto
+func (r *Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	rate := "unlimited"
+	if r.Rate > 0 {
+		rate = fmt.Sprintf("%.1f/s", r.Rate)
+	}
+	fmt.Fprintf(tw, "Requests:\t%d (%d failed)\n", r.Requests, r.Failures)
+	fmt.Fprintf(tw, "Duration:\t%s\n", r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(tw, "Rate:\t%s requested, %.1f/s achieved (concurrency %d)\n", rate, r.Throughput, r.Concurrency)
+	fmt.Fprintln(tw)
+
+	l := r.Latencies
+	fmt.Fprintln(tw, "Latency\tmin\tmean\tp50\tp90\tp95\tp99\tmax")
+	fmt.Fprintf(tw, "\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		round(l.Min), round(l.Mean), round(l.P50), round(l.P90), round(l.P95), round(l.P99), round(l.Max))
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "Outcome\tcount\t%")
+	for _, outcome := range r.Outcomes {
+		fmt.Fprintf(tw, "%s\t%d\t%.1f\n", outcome.Name, outcome.Count, 100*float64(outcome.Count)/float64(r.Requests))
+	}
+	return tw.Flush()
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
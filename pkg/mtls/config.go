@@ -0,0 +1,105 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ServerConfig cria a configuração TLS do servidor a partir do Reloader. Com
+// bundle de CAs o certificado do cliente é obrigatório e, se allowedClients não
+// for vazio, sua identidade (CN, SAN DNS ou URI) precisa estar na lista.
+func ServerConfig(r *Reloader, allowedClients []string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Necessário para http.Server.ListenAndServeTLS("", "")
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.Certificate(), nil
+		},
+		// Cada handshake usa o material mais recente, sem reiniciar o servidor
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.Certificate()},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if pool := r.CAPool(); pool != nil {
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+				config.ClientCAs = pool
+				config.VerifyConnection = verifyClientIdentity(allowedClients)
+			}
+			return config, nil
+		},
+	}
+}
+
+// ClientConfig cria a configuração TLS do cliente, apresentando o certificado
+// do Reloader e validando o servidor contra o bundle de CAs atual
+func ClientConfig(r *Reloader, serverName string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.Certificate(), nil
+		},
+		// A verificação padrão usaria um RootCAs fixo; VerifyConnection valida a
+		// cadeia com o bundle recarregado, então a verificação não é desativada
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			return verifyServer(cs, r.CAPool())
+		},
+	}
+}
+
+// Identities lista os nomes aceitos como identidade de um certificado
+func Identities(cert *x509.Certificate) []string {
+	var identities []string
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, cert.Subject.CommonName)
+	}
+	identities = append(identities, cert.DNSNames...)
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+	return identities
+}
+
+func verifyServer(cs tls.ConnectionState, roots *x509.CertPool) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("server presented no certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		DNSName:       cs.ServerName,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	return err
+}
+
+func verifyClientIdentity(allowedClients []string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(allowedClients) == 0 {
+			return nil
+		}
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("client presented no certificate")
+		}
+
+		identities := Identities(cs.PeerCertificates[0])
+		for _, identity := range identities {
+			if slices.Contains(allowedClients, identity) {
+				return nil
+			}
+		}
+		return fmt.Errorf("client identity %v is not allowed", identities)
+	}
+}
@@ -0,0 +1,205 @@
+package mtls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+// issue grava em dir os arquivos <name>.crt, <name>.key e ca.crt
+func (ca *testCA) issue(t *testing.T, dir, name string, usage x509.ExtKeyUsage) (certFile, keyFile, caFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	caFile = filepath.Join(dir, "ca.crt")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "PRIVATE KEY", keyDER)
+	writePEM(t, caFile, "CERTIFICATE", ca.cert.Raw)
+	return certFile, keyFile, caFile
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	otherCA := newTestCA(t)
+	dir := t.TempDir()
+
+	serverCert, serverKey, caFile := ca.issue(t, dir, "service-b", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey, _ := ca.issue(t, dir, "service-a", x509.ExtKeyUsageClientAuth)
+	intruderCert, intruderKey, _ := ca.issue(t, dir, "intruder", x509.ExtKeyUsageClientAuth)
+
+	otherDir := t.TempDir()
+	foreignCert, foreignKey, foreignCA := otherCA.issue(t, otherDir, "service-a", x509.ExtKeyUsageClientAuth)
+
+	serverReloader, err := NewReloader(serverCert, serverKey, caFile)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(Identities(r.TLS.PeerCertificates[0])[0]))
+	}))
+	server.TLS = ServerConfig(serverReloader, []string{"service-a"})
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		certFile  string
+		keyFile   string
+		caFile    string
+		expectErr bool
+	}{
+		{name: "allowed client", certFile: clientCert, keyFile: clientKey, caFile: caFile},
+		{name: "client identity not allowed", certFile: intruderCert, keyFile: intruderKey, caFile: caFile, expectErr: true},
+		{name: "client signed by unknown CA", certFile: foreignCert, keyFile: foreignKey, caFile: caFile, expectErr: true},
+		{name: "server signed by unknown CA", certFile: foreignCert, keyFile: foreignKey, caFile: foreignCA, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reloader, err := NewReloader(tt.certFile, tt.keyFile, tt.caFile)
+			require.NoError(t, err)
+
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: ClientConfig(reloader, "service-b")}}
+			resp, err := client.Get(server.URL)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+
+	t.Run("wrong server name", func(t *testing.T) {
+		reloader, err := NewReloader(clientCert, clientKey, caFile)
+		require.NoError(t, err)
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: ClientConfig(reloader, "other-service")}}
+		_, err = client.Get(server.URL)
+		assert.Error(t, err)
+	})
+}
+
+func TestReloaderWatch(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+
+	certFile, keyFile, caFile := ca.issue(t, dir, "service-a", x509.ExtKeyUsageClientAuth)
+	reloader, err := NewReloader(certFile, keyFile, caFile)
+	require.NoError(t, err)
+	original := reloader.Certificate()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Watch(ctx, 10*time.Millisecond)
+
+	// Um par inválido não substitui o certificado em uso
+	require.NoError(t, os.WriteFile(keyFile, []byte("invalid"), 0o600))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(keyFile, future, future))
+	time.Sleep(50 * time.Millisecond)
+	assert.Same(t, original, reloader.Certificate())
+
+	// Rotação: novo certificado emitido para o mesmo nome
+	ca.issue(t, dir, "service-a", x509.ExtKeyUsageClientAuth)
+	future = future.Add(time.Minute)
+	for _, file := range []string{certFile, keyFile} {
+		require.NoError(t, os.Chtimes(file, future, future))
+	}
+
+	assert.Eventually(t, func() bool {
+		return reloader.Certificate() != original
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestNewReloaderErrors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := newTestCA(t).issue(t, dir, "service-a", x509.ExtKeyUsageClientAuth)
+	emptyCA := filepath.Join(dir, "empty.crt")
+	require.NoError(t, os.WriteFile(emptyCA, []byte("no certificates here"), 0o600))
+
+	tests := []struct {
+		name     string
+		certFile string
+		keyFile  string
+		caFile   string
+	}{
+		{name: "missing key file", certFile: certFile},
+		{name: "nonexistent certificate", certFile: filepath.Join(dir, "missing.crt"), keyFile: keyFile},
+		{name: "empty CA bundle", certFile: certFile, keyFile: keyFile, caFile: emptyCA},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReloader(tt.certFile, tt.keyFile, tt.caFile)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package openapi
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+)
+
+// SpecHandler serve a especificação em /openapi.json
+func SpecHandler(spec []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if _, err := w.Write(spec); err != nil {
+			log.Printf("Error writing OpenAPI spec: %v", err)
+		}
+	}
+}
+
+// swaggerUIVersion é a versão do swagger-ui-dist carregada do CDN
+const swaggerUIVersion = "5.11.0"
+
+var swaggerUITemplate = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@{{.Version}}/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: {{.SpecURL}}, dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`))
+
+// SwaggerUIHandler serve a Swagger UI apontando para specURL (ex: "/openapi.json")
+func SwaggerUIHandler(title, specURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err := swaggerUITemplate.Execute(w, map[string]string{
+			"Title":   fmt.Sprintf("%s - API docs", title),
+			"Version": swaggerUIVersion,
+			"SpecURL": specURL,
+		})
+		if err != nil {
+			log.Printf("Error rendering Swagger UI: %v", err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Package openapi serve a especificação OpenAPI dos serviços, a Swagger UI e,
+// em modo de teste, valida as respostas contra a especificação.
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// Validator valida as respostas contra uma especificação OpenAPI 3. As
+// requisições ficam com os serviços: o body de cada rota é decodificado e
+// validado pelo handler (pkg/decode), que define um único formato de erro.
+type Validator struct {
+	router routers.Router
+}
+
+// NewValidator carrega e valida a especificação (JSON ou YAML)
+func NewValidator(spec []byte) (*Validator, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("error loading OpenAPI spec: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+
+	// As rotas são casadas apenas pelo path, independente do host em que o serviço roda
+	doc.Servers = nil
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("error building OpenAPI router: %w", err)
+	}
+
+	return &Validator{router: router}, nil
+}
+
+// Middleware substitui por 500 as respostas que não seguem a especificação.
+// Pensado para testes e ambientes de desenvolvimento, pois bufferiza cada
+// resposta. Rotas não descritas na especificação passam direto.
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &responseRecorder{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+			},
+			Status:  rec.status,
+			Header:  rec.header,
+			Body:    io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+			Options: &openapi3filter.Options{IncludeResponseStatus: true},
+		})
+		if err != nil {
+			log.Printf("Response does not match OpenAPI spec (%s %s -> %d): %v", r.Method, r.URL.Path, rec.status, err)
+			writeError(w, http.StatusInternalServerError, "response does not match OpenAPI spec: "+err.Error())
+			return
+		}
+
+		rec.flush(w)
+	})
+}
+
+// writeError responde no formato de erro dos serviços: {"message": "..."}
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string]string{"message": message}); err != nil {
+		log.Printf("Error writing JSON response: %v", err)
+	}
+}
+
+// responseRecorder bufferiza a resposta para validá-la antes do envio
+type responseRecorder struct {
+	header      http.Header
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+	r.status = status
+	r.wroteHeader = true
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(data)
+}
+
+func (r *responseRecorder) flush(w http.ResponseWriter) {
+	for key, values := range r.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(r.status)
+	w.Write(r.body.Bytes())
+}
@@ -0,0 +1,141 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "test", "version": "1.0.0"},
+  "servers": [{"url": "http://localhost:8080"}],
+  "paths": {
+    "/weather": {
+      "post": {
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "type": "object",
+                "required": ["cep"],
+                "additionalProperties": false,
+                "properties": {"cep": {"type": "string"}}
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "ok",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object",
+                  "required": ["city"],
+                  "properties": {"city": {"type": "string"}}
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestValidatorMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		contentType    string
+		body           string
+		response       string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "valid response",
+			path:           "/weather",
+			contentType:    "application/json",
+			body:           `{"cep":"26140040"}`,
+			response:       `{"city":"Belford Roxo"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"city":"Belford Roxo"}`,
+		},
+		{
+			name:           "response outside the spec",
+			path:           "/weather",
+			contentType:    "application/json",
+			body:           `{"cep":"26140040"}`,
+			response:       `{"temp":25}`,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "request body left to the service",
+			path:           "/weather",
+			contentType:    "text/plain",
+			body:           `{"cep":`,
+			response:       `{"city":"Belford Roxo"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"city":"Belford Roxo"}`,
+		},
+		{
+			name:           "route outside the spec",
+			path:           "/healthz",
+			response:       `{"status":"up"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"up"}`,
+		},
+	}
+
+	validator, err := NewValidator([]byte(testSpec))
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlerCalled := false
+			handler := validator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerCalled = true
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.response))
+			}))
+
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.True(t, handlerCalled)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestNewValidatorInvalidSpec(t *testing.T) {
+	_, err := NewValidator([]byte(`{"openapi": "3.0.3", "info": {"title": "test"}, "paths": {}}`))
+	assert.Error(t, err)
+}
+
+func TestSpecAndSwaggerUIHandlers(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SpecHandler([]byte(testSpec)).ServeHTTP(rec, httptest.NewRequest("GET", "/openapi.json", nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, testSpec, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	SwaggerUIHandler("Service A", "/openapi.json").ServeHTTP(rec, httptest.NewRequest("GET", "/docs", nil))
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), `url: "/openapi.json"`)
+	assert.Contains(t, rec.Body.String(), "Service A - API docs")
+}
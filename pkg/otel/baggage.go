@@ -0,0 +1,48 @@
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// Chaves de baggage propagadas entre os serviços
+const (
+	BaggageClientID      = "client.id"
+	BaggageRequestSource = "request.source"
+)
+
+// ContextWithBaggage adiciona os membros ao baggage do contexto, ignorando valores vazios
+func ContextWithBaggage(ctx context.Context, members map[string]string) (context.Context, error) {
+	bag := baggage.FromContext(ctx)
+	for key, value := range members {
+		if value == "" {
+			continue
+		}
+
+		member, err := baggage.NewMemberRaw(key, value)
+		if err != nil {
+			return ctx, err
+		}
+
+		if bag, err = bag.SetMember(member); err != nil {
+			return ctx, err
+		}
+	}
+
+	return baggage.ContextWithBaggage(ctx, bag), nil
+}
+
+// BaggageAttributes converte os membros conhecidos do baggage em atributos de span
+func BaggageAttributes(ctx context.Context) []attribute.KeyValue {
+	bag := baggage.FromContext(ctx)
+
+	var attrs []attribute.KeyValue
+	for _, key := range []string{BaggageClientID, BaggageRequestSource} {
+		if value := bag.Member(key).Value(); value != "" {
+			attrs = append(attrs, attribute.String(key, value))
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,146 @@
+package otel
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileEnv aponta para o arquivo declarativo opcional de telemetria
+const ConfigFileEnv = "OTEL_CONFIG_FILE"
+
+const (
+	defaultExporter       = "zipkin"
+	defaultZipkinEndpoint = "http://localhost:9411/api/v2/spans"
+	defaultOTLPProtocol   = "http/protobuf"
+	defaultSampler        = "parentbased_always_on"
+)
+
+// fileConfig é o formato do arquivo apontado por OTEL_CONFIG_FILE
+type fileConfig struct {
+	ServiceName string   `yaml:"service_name"`
+	Disabled    bool     `yaml:"disabled"`
+	Exporter    string   `yaml:"traces_exporter"`
+	Sampler     string   `yaml:"traces_sampler"`
+	SamplerArg  string   `yaml:"traces_sampler_arg"`
+	Propagators []string `yaml:"propagators"`
+	Zipkin      struct {
+		Endpoint string `yaml:"endpoint"`
+	} `yaml:"zipkin"`
+	OTLP struct {
+		Endpoint string            `yaml:"endpoint"`
+		Protocol string            `yaml:"protocol"`
+		Headers  map[string]string `yaml:"headers"`
+		Insecure bool              `yaml:"insecure"`
+	} `yaml:"otlp"`
+	ResourceAttributes map[string]string `yaml:"resource_attributes"`
+}
+
+// loadConfig resolve a configuração em camadas: padrões, arquivo declarativo,
+// variáveis OTEL_* padrão do SDK e, por último, as opções do serviço.
+func loadConfig(serviceName string, opts []Option) (*config, error) {
+	cfg := &config{
+		serviceName:    serviceName,
+		exporter:       defaultExporter,
+		zipkinEndpoint: defaultZipkinEndpoint,
+		otlpProtocol:   defaultOTLPProtocol,
+		sampler:        defaultSampler,
+		propagators:    DefaultPropagators,
+	}
+
+	if path := os.Getenv(ConfigFileEnv); path != "" {
+		if err := cfg.applyFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg, nil
+}
+
+func (c *config) applyFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", ConfigFileEnv, err)
+	}
+
+	var file fileConfig
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("error parsing %s: %w", path, err)
+	}
+
+	setIfNotEmpty(&c.serviceName, file.ServiceName)
+	c.disabled = file.Disabled
+	setIfNotEmpty(&c.exporter, file.Exporter)
+	setIfNotEmpty(&c.sampler, file.Sampler)
+	setIfNotEmpty(&c.samplerArg, file.SamplerArg)
+	if len(file.Propagators) > 0 {
+		c.propagators = file.Propagators
+	}
+	setIfNotEmpty(&c.zipkinEndpoint, file.Zipkin.Endpoint)
+	setIfNotEmpty(&c.otlpEndpoint, file.OTLP.Endpoint)
+	setIfNotEmpty(&c.otlpProtocol, file.OTLP.Protocol)
+	c.otlpHeaders = file.OTLP.Headers
+	c.otlpInsecure = file.OTLP.Insecure
+	c.resourceAttributes = file.ResourceAttributes
+
+	return nil
+}
+
+func (c *config) applyEnv() error {
+	if value := os.Getenv("OTEL_SDK_DISABLED"); value != "" {
+		disabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid OTEL_SDK_DISABLED: %w", err)
+		}
+		c.disabled = disabled
+	}
+
+	setIfNotEmpty(&c.serviceName, os.Getenv("OTEL_SERVICE_NAME"))
+	setIfNotEmpty(&c.sampler, os.Getenv("OTEL_TRACES_SAMPLER"))
+	setIfNotEmpty(&c.samplerArg, os.Getenv("OTEL_TRACES_SAMPLER_ARG"))
+	setIfNotEmpty(&c.zipkinEndpoint, os.Getenv("OTEL_EXPORTER_ZIPKIN_ENDPOINT"))
+	setIfNotEmpty(&c.otlpProtocol, firstEnv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "OTEL_EXPORTER_OTLP_PROTOCOL"))
+	if value := os.Getenv("OTEL_PROPAGATORS"); value != "" {
+		c.propagators = strings.Split(value, ",")
+	}
+
+	// O exporter OTLP já lê endpoint, headers, TLS e timeout do ambiente;
+	// aqui apenas descartamos os valores do arquivo para que o ambiente prevaleça.
+	otlpEndpoint := firstEnv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT")
+	if otlpEndpoint != "" {
+		c.otlpEndpoint = ""
+		c.otlpInsecure = false
+	}
+	if firstEnv("OTEL_EXPORTER_OTLP_TRACES_HEADERS", "OTEL_EXPORTER_OTLP_HEADERS") != "" {
+		c.otlpHeaders = nil
+	}
+
+	// Sem OTEL_TRACES_EXPORTER explícito, um endpoint OTLP no ambiente seleciona o OTLP
+	if exporter := os.Getenv("OTEL_TRACES_EXPORTER"); exporter != "" {
+		c.exporter = exporter
+	} else if otlpEndpoint != "" {
+		c.exporter = "otlp"
+	}
+
+	return nil
+}
+
+func firstEnv(keys ...string) string {
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,170 @@
+package otel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "otel.yaml")
+	err := os.WriteFile(file, []byte(`
+service_name: from-file
+traces_exporter: otlp
+traces_sampler: traceidratio
+traces_sampler_arg: "0.25"
+propagators: [tracecontext]
+otlp:
+  endpoint: http://collector:4318/v1/traces
+  headers:
+    x-api-key: secret
+resource_attributes:
+  team: weather
+`), 0o600)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		opts     []Option
+		expected func(t *testing.T, cfg *config)
+	}{
+		{
+			name: "defaults",
+			expected: func(t *testing.T, cfg *config) {
+				assert.Equal(t, "service-test", cfg.serviceName)
+				assert.Equal(t, "zipkin", cfg.exporter)
+				assert.Equal(t, defaultZipkinEndpoint, cfg.zipkinEndpoint)
+				assert.Equal(t, "parentbased_always_on", cfg.sampler)
+				assert.Equal(t, DefaultPropagators, cfg.propagators)
+			},
+		},
+		{
+			name: "config file",
+			env:  map[string]string{ConfigFileEnv: file},
+			expected: func(t *testing.T, cfg *config) {
+				assert.Equal(t, "from-file", cfg.serviceName)
+				assert.Equal(t, "otlp", cfg.exporter)
+				assert.Equal(t, "traceidratio", cfg.sampler)
+				assert.Equal(t, "0.25", cfg.samplerArg)
+				assert.Equal(t, []string{"tracecontext"}, cfg.propagators)
+				assert.Equal(t, "http://collector:4318/v1/traces", cfg.otlpEndpoint)
+				assert.Equal(t, map[string]string{"x-api-key": "secret"}, cfg.otlpHeaders)
+				assert.Equal(t, map[string]string{"team": "weather"}, cfg.resourceAttributes)
+			},
+		},
+		{
+			name: "environment overrides file",
+			env: map[string]string{
+				ConfigFileEnv:                 file,
+				"OTEL_SERVICE_NAME":           "from-env",
+				"OTEL_TRACES_SAMPLER":         "always_off",
+				"OTEL_PROPAGATORS":            "b3,baggage",
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://platform:4318",
+				"OTEL_EXPORTER_OTLP_HEADERS":  "x-api-key=platform",
+			},
+			expected: func(t *testing.T, cfg *config) {
+				assert.Equal(t, "from-env", cfg.serviceName)
+				assert.Equal(t, "always_off", cfg.sampler)
+				assert.Equal(t, []string{"b3", "baggage"}, cfg.propagators)
+				// Endpoint e headers ficam a cargo do exporter, que lê o ambiente
+				assert.Empty(t, cfg.otlpEndpoint)
+				assert.Nil(t, cfg.otlpHeaders)
+			},
+		},
+		{
+			name: "otlp endpoint selects otlp exporter",
+			env:  map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://platform:4318/v1/traces"},
+			expected: func(t *testing.T, cfg *config) {
+				assert.Equal(t, "otlp", cfg.exporter)
+			},
+		},
+		{
+			name: "explicit exporter wins over otlp endpoint",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "http://platform:4318",
+				"OTEL_TRACES_EXPORTER":        "console",
+			},
+			expected: func(t *testing.T, cfg *config) {
+				assert.Equal(t, "console", cfg.exporter)
+			},
+		},
+		{
+			name: "options override environment and ignore empty values",
+			env: map[string]string{
+				"OTEL_EXPORTER_ZIPKIN_ENDPOINT": "http://env:9411/api/v2/spans",
+				"OTEL_PROPAGATORS":              "b3",
+				"OTEL_SDK_DISABLED":             "true",
+			},
+			opts: []Option{
+				WithZipkinEndpoint("http://viper:9411/api/v2/spans"),
+				WithPropagators(""),
+				WithEnvironment(""),
+			},
+			expected: func(t *testing.T, cfg *config) {
+				assert.Equal(t, "http://viper:9411/api/v2/spans", cfg.zipkinEndpoint)
+				assert.Equal(t, []string{"b3"}, cfg.propagators)
+				assert.Empty(t, cfg.environment)
+				assert.True(t, cfg.disabled)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := loadConfig("service-test", tt.opts)
+			assert.NoError(t, err)
+			tt.expected(t, cfg)
+		})
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	t.Run("missing config file", func(t *testing.T) {
+		t.Setenv(ConfigFileEnv, filepath.Join(t.TempDir(), "missing.yaml"))
+		_, err := loadConfig("service-test", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid OTEL_SDK_DISABLED", func(t *testing.T) {
+		t.Setenv("OTEL_SDK_DISABLED", "maybe")
+		_, err := loadConfig("service-test", nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewSampler(t *testing.T) {
+	tests := []struct {
+		name        string
+		sampler     string
+		arg         string
+		description string
+		expectErr   bool
+	}{
+		{"always on", "always_on", "", "AlwaysOnSampler", false},
+		{"always off", "always_off", "", "AlwaysOffSampler", false},
+		{"ratio", "traceidratio", "0.5", "TraceIDRatioBased{0.5}", false},
+		{"parent based ratio", "parentbased_traceidratio", "0.1", "ParentBased{root:TraceIDRatioBased{0.1},remoteParentSampled:AlwaysOnSampler,remoteParentNotSampled:AlwaysOffSampler,localParentSampled:AlwaysOnSampler,localParentNotSampled:AlwaysOffSampler}", false},
+		{"invalid ratio", "traceidratio", "2", "", true},
+		{"unknown sampler", "jaeger_remote", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sampler, err := newSampler(tt.sampler, tt.arg)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.description, sampler.Description())
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+var (
+	exporterMu       sync.RWMutex
+	exporterEndpoint string
+)
+
+// ExporterEndpoint retorna a URL do coletor usado pelo exporter ativo, ou vazio
+// quando não há exporter de rede (console, none ou SDK desabilitado).
+func ExporterEndpoint() string {
+	exporterMu.RLock()
+	defer exporterMu.RUnlock()
+
+	return exporterEndpoint
+}
+
+func setExporterEndpoint(endpoint string) {
+	exporterMu.Lock()
+	defer exporterMu.Unlock()
+
+	exporterEndpoint = endpoint
+}
+
+// resolveExporterEndpoint replica a resolução de endpoint feita pelos exporters
+func resolveExporterEndpoint(cfg *config) string {
+	if cfg.disabled {
+		return ""
+	}
+
+	switch strings.ToLower(cfg.exporter) {
+	case "zipkin":
+		return cfg.zipkinEndpoint
+	case "otlp":
+		if cfg.otlpEndpoint != "" {
+			return cfg.otlpEndpoint
+		}
+		if endpoint := firstEnv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+			return endpoint
+		}
+		if strings.ToLower(cfg.otlpProtocol) == "grpc" {
+			return "http://localhost:4317"
+		}
+		return "http://localhost:4318"
+	default:
+		return ""
+	}
+}
+
+// newExporter cria o exporter de traces configurado; "none" retorna nil
+func newExporter(ctx context.Context, cfg *config) (trace.SpanExporter, error) {
+	switch strings.ToLower(cfg.exporter) {
+	case "zipkin":
+		return zipkin.New(cfg.zipkinEndpoint)
+	case "otlp":
+		return newOTLPExporter(ctx, cfg)
+	case "console":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown traces exporter: %q", cfg.exporter)
+	}
+}
+
+func newOTLPExporter(ctx context.Context, cfg *config) (trace.SpanExporter, error) {
+	switch strings.ToLower(cfg.otlpProtocol) {
+	case "grpc":
+		var opts []otlptracegrpc.Option
+		if cfg.otlpEndpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(cfg.otlpEndpoint))
+		}
+		if len(cfg.otlpHeaders) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(cfg.otlpHeaders))
+		}
+		if cfg.otlpInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case "http/protobuf":
+		var opts []otlptracehttp.Option
+		if cfg.otlpEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.otlpEndpoint))
+		}
+		if len(cfg.otlpHeaders) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.otlpHeaders))
+		}
+		if cfg.otlpInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol: %q", cfg.otlpProtocol)
+	}
+}
+
+// newSampler traduz os nomes de OTEL_TRACES_SAMPLER para samplers do SDK
+func newSampler(name, arg string) (trace.Sampler, error) {
+	ratio := 1.0
+	if arg != "" {
+		var err error
+		if ratio, err = strconv.ParseFloat(arg, 64); err != nil || ratio < 0 || ratio > 1 {
+			return nil, fmt.Errorf("invalid sampler argument %q: must be a ratio between 0 and 1", arg)
+		}
+	}
+
+	switch strings.ToLower(name) {
+	case "always_on":
+		return trace.AlwaysSample(), nil
+	case "always_off":
+		return trace.NeverSample(), nil
+	case "traceidratio":
+		return trace.TraceIDRatioBased(ratio), nil
+	case "parentbased_always_on":
+		return trace.ParentBased(trace.AlwaysSample()), nil
+	case "parentbased_always_off":
+		return trace.ParentBased(trace.NeverSample()), nil
+	case "parentbased_traceidratio":
+		return trace.ParentBased(trace.TraceIDRatioBased(ratio)), nil
+	default:
+		return nil, fmt.Errorf("unknown traces sampler: %q", name)
+	}
+}
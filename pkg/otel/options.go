@@ -0,0 +1,78 @@
+package otel
+
+import "strings"
+
+// Option sobrescreve a configuração do OpenTelemetry carregada do arquivo e
+// das variáveis OTEL_*. Valores vazios são ignorados, de forma que chaves não
+// configuradas no serviço não apagam o que veio do ambiente.
+type Option func(*config)
+
+type config struct {
+	serviceName        string
+	disabled           bool
+	exporter           string
+	zipkinEndpoint     string
+	otlpEndpoint       string
+	otlpProtocol       string
+	otlpHeaders        map[string]string
+	otlpInsecure       bool
+	sampler            string
+	samplerArg         string
+	propagators        []string
+	resourceAttributes map[string]string
+	version            string
+	commit             string
+	environment        string
+}
+
+// WithPropagators define os propagadores de contexto pelo nome (ver NewPropagator)
+func WithPropagators(names ...string) Option {
+	return func(c *config) {
+		if strings.TrimSpace(strings.Join(names, "")) != "" {
+			c.propagators = names
+		}
+	}
+}
+
+// WithVersion define a versão e o commit do binário, normalmente injetados via ldflags
+func WithVersion(version, commit string) Option {
+	return func(c *config) {
+		setIfNotEmpty(&c.version, version)
+		setIfNotEmpty(&c.commit, commit)
+	}
+}
+
+// WithEnvironment define o atributo deployment.environment (ex: development, production)
+func WithEnvironment(environment string) Option {
+	return func(c *config) {
+		setIfNotEmpty(&c.environment, environment)
+	}
+}
+
+// WithZipkinEndpoint define a URL do coletor Zipkin
+func WithZipkinEndpoint(endpoint string) Option {
+	return func(c *config) {
+		setIfNotEmpty(&c.zipkinEndpoint, endpoint)
+	}
+}
+
+// WithExporter define o exporter de traces (zipkin, otlp, console ou none)
+func WithExporter(exporter string) Option {
+	return func(c *config) {
+		setIfNotEmpty(&c.exporter, exporter)
+	}
+}
+
+// WithSampler define o sampler pelo nome de OTEL_TRACES_SAMPLER e seu argumento
+func WithSampler(sampler, arg string) Option {
+	return func(c *config) {
+		setIfNotEmpty(&c.sampler, sampler)
+		setIfNotEmpty(&c.samplerArg, arg)
+	}
+}
+
+func setIfNotEmpty(dst *string, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		*dst = value
+	}
+}
@@ -0,0 +1,41 @@
+// Package oteltest reúne os auxiliares dos testes que verificam spans.
+package oteltest
+
+import (
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Record registra um recorder de spans em memória como TracerProvider global
+// e restaura o provider anterior ao fim do teste
+func Record(t testing.TB) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// SpanTree descreve cada span como "nome <- pai : status" para comparar a árvore
+func SpanTree(spans []sdktrace.ReadOnlySpan) []string {
+	names := make(map[string]string, len(spans))
+	for _, s := range spans {
+		names[s.SpanContext().SpanID().String()] = s.Name()
+	}
+
+	tree := make([]string, 0, len(spans))
+	for _, s := range spans {
+		parent := "root"
+		if s.Parent().IsValid() {
+			parent = names[s.Parent().SpanID().String()]
+		}
+		tree = append(tree, fmt.Sprintf("%s <- %s : %s", s.Name(), parent, s.Status().Code))
+	}
+	return tree
+}
@@ -0,0 +1,69 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewPropagator(t *testing.T) {
+	tests := []struct {
+		name           string
+		propagators    []string
+		expectedFields []string
+		expectErr      bool
+	}{
+		{"default", nil, []string{"traceparent", "tracestate", "baggage"}, false},
+		{"tracecontext only", []string{"tracecontext"}, []string{"traceparent", "tracestate"}, false},
+		{"b3 single header", []string{"tracecontext", " B3 "}, []string{"traceparent", "tracestate", "b3"}, false},
+		{"b3 multi header", []string{"b3multi"}, []string{"x-b3-traceid", "x-b3-spanid", "x-b3-sampled", "x-b3-flags"}, false},
+		{"unknown propagator", []string{"jaeger"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			propagator, err := NewPropagator(tt.propagators)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Subset(t, propagator.Fields(), tt.expectedFields)
+		})
+	}
+}
+
+func TestPropagatorRoundTrip(t *testing.T) {
+	propagator, err := NewPropagator(nil)
+	assert.NoError(t, err)
+
+	// Monta um contexto com span remoto e baggage
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+	ctx, err = ContextWithBaggage(ctx, map[string]string{
+		BaggageClientID:      "client 42",
+		BaggageRequestSource: "cli",
+		"empty":              "",
+	})
+	assert.NoError(t, err)
+
+	// Injeta nos headers e extrai do outro lado
+	headers := http.Header{}
+	propagator.Inject(ctx, propagation.HeaderCarrier(headers))
+	extracted := propagator.Extract(context.Background(), propagation.HeaderCarrier(headers))
+
+	assert.Equal(t, spanCtx.TraceID(), trace.SpanContextFromContext(extracted).TraceID())
+	assert.Equal(t, "client 42", baggage.FromContext(extracted).Member(BaggageClientID).Value())
+	assert.Equal(t, 2, baggage.FromContext(extracted).Len())
+	assert.Len(t, BaggageAttributes(extracted), 2)
+}
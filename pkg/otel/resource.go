@@ -0,0 +1,59 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// ServiceCommitKey identifica o commit a partir do qual o binário foi gerado
+const ServiceCommitKey = attribute.Key("service.commit")
+
+// newResource descreve o serviço combinando os detectores do SDK (host, SO,
+// processo, container, arquivo de configuração e OTEL_RESOURCE_ATTRIBUTES) com
+// os valores configurados.
+// Os valores configurados têm precedência sobre os detectados.
+func newResource(ctx context.Context, cfg *config) (*resource.Resource, error) {
+	fileAttrs := make([]attribute.KeyValue, 0, len(cfg.resourceAttributes))
+	for key, value := range cfg.resourceAttributes {
+		fileAttrs = append(fileAttrs, attribute.String(key, value))
+	}
+
+	attrs := []attribute.KeyValue{semconv.ServiceNameKey.String(cfg.serviceName)}
+	if cfg.version != "" {
+		attrs = append(attrs, semconv.ServiceVersionKey.String(cfg.version))
+	}
+	if cfg.commit != "" {
+		attrs = append(attrs, ServiceCommitKey.String(cfg.commit))
+	}
+	if cfg.environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironmentKey.String(cfg.environment))
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithOSType(),
+		// Argumentos de linha de comando ficam de fora para não vazar segredos
+		resource.WithProcessPID(),
+		resource.WithProcessExecutableName(),
+		resource.WithProcessRuntimeName(),
+		resource.WithProcessRuntimeVersion(),
+		resource.WithContainer(),
+		resource.WithAttributes(fileAttrs...),
+		resource.WithFromEnv(),
+		resource.WithAttributes(attrs...),
+	)
+
+	// Detectores que falham parcialmente não impedem a inicialização
+	if errors.Is(err, resource.ErrPartialResource) {
+		log.Printf("Warning: partial resource detection: %v", err)
+		return res, nil
+	}
+	return res, err
+}
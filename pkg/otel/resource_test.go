@@ -0,0 +1,42 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+func TestNewResource(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "team=weather,deployment.environment=from-env")
+
+	cfg := &config{serviceName: "service-test"}
+	WithVersion("1.2.3", "abc1234")(cfg)
+	WithEnvironment("staging")(cfg)
+
+	res, err := newResource(context.Background(), cfg)
+	assert.NoError(t, err)
+
+	attrs := attribute.NewSet(res.Attributes()...)
+	get := func(key attribute.Key) string {
+		value, _ := attrs.Value(key)
+		return value.Emit()
+	}
+
+	assert.Equal(t, "service-test", get(semconv.ServiceNameKey))
+	assert.Equal(t, "1.2.3", get(semconv.ServiceVersionKey))
+	assert.Equal(t, "abc1234", get(ServiceCommitKey))
+
+	// Valores configurados têm precedência sobre OTEL_RESOURCE_ATTRIBUTES
+	assert.Equal(t, "staging", get(semconv.DeploymentEnvironmentKey))
+	assert.Equal(t, "weather", get("team"))
+
+	// Atributos detectados do host e do processo
+	assert.NotEmpty(t, get(semconv.HostNameKey))
+	assert.NotEmpty(t, get(semconv.ProcessPIDKey))
+	assert.NotEmpty(t, get(semconv.ProcessRuntimeNameKey))
+	_, hasArgs := attrs.Value(semconv.ProcessCommandArgsKey)
+	assert.False(t, hasArgs)
+}
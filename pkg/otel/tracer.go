@@ -7,38 +7,58 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/zipkin"
-	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 )
 
-// InitTracer configura o OpenTelemetry com Zipkin exporter
-func InitTracer(serviceName, zipkinURL string) (func(), error) {
+// InitTracer configura o OpenTelemetry a partir das variáveis OTEL_* padrão, do
+// arquivo opcional apontado por OTEL_CONFIG_FILE e das opções informadas, que
+// têm a maior precedência. serviceName é usado quando OTEL_SERVICE_NAME não é definido.
+func InitTracer(serviceName string, opts ...Option) (func(), error) {
+	cfg, err := loadConfig(serviceName, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load telemetry config: %w", err)
+	}
 
-	// Criar Zipkin exporter
-	exporter, err := zipkin.New(zipkinURL)
+	// Configura a propagação de contexto entre serviços
+	propagator, err := NewPropagator(cfg.propagators)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create zipkin exporter: %w", err)
+		return nil, fmt.Errorf("failed to create propagator: %w", err)
 	}
+	otel.SetTextMapPropagator(propagator)
 
-	// Configura o resource com informações do serviço
-	res, err := resource.New(context.Background(),
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String(serviceName),
-			semconv.ServiceVersionKey.String("1.0.0"),
-		),
-	)
+	setExporterEndpoint(resolveExporterEndpoint(cfg))
+
+	if cfg.disabled {
+		log.Printf("OpenTelemetry SDK disabled for service: %s", cfg.serviceName)
+		return func() {}, nil
+	}
+
+	sampler, err := newSampler(cfg.sampler, cfg.samplerArg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sampler: %w", err)
+	}
+
+	// Cria o exporter configurado
+	exporter, err := newExporter(context.Background(), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s exporter: %w", cfg.exporter, err)
+	}
+
+	// Configura o resource com informações do serviço e do ambiente
+	res, err := newResource(context.Background(), cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource: %w", err)
 	}
 
 	// Configura o TracerProvider
-	tp := trace.NewTracerProvider(
-		trace.WithBatcher(exporter),
+	tpOpts := []trace.TracerProviderOption{
 		trace.WithResource(res),
-		trace.WithSampler(trace.AlwaysSample()),
-	)
+		trace.WithSampler(sampler),
+	}
+	if exporter != nil {
+		tpOpts = append(tpOpts, trace.WithBatcher(exporter))
+	}
+	tp := trace.NewTracerProvider(tpOpts...)
 
 	// Registra globalmente
 	otel.SetTracerProvider(tp)
@@ -53,6 +73,6 @@ func InitTracer(serviceName, zipkinURL string) (func(), error) {
 		}
 	}
 
-	log.Printf("OpenTelemetry initialized for service: %s", serviceName)
+	log.Printf("OpenTelemetry initialized for service: %s (exporter: %s, sampler: %s)", cfg.serviceName, cfg.exporter, cfg.sampler)
 	return shutdown, nil
 }
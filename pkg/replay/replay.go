@@ -0,0 +1,347 @@
+// Package replay grava trocas HTTP reais em golden files (com segredos
+// removidos) e as reproduz de forma determinística em testes e execuções locais.
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mode define o comportamento do Recorder
+type Mode string
+
+const (
+	// ModeOff repassa as requisições sem gravar
+	ModeOff Mode = "off"
+	// ModeRecord repassa as requisições e grava as trocas no golden file
+	ModeRecord Mode = "record"
+	// ModeReplay responde a partir do golden file, sem acessar a rede
+	ModeReplay Mode = "replay"
+)
+
+// Redacted substitui os valores sensíveis gravados
+const Redacted = "REDACTED"
+
+// DefaultRedactedHeaders e DefaultRedactedParams nunca são gravados em claro
+var (
+	DefaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+	DefaultRedactedParams  = []string{"key", "api_key", "apikey", "token", "access_token"}
+)
+
+// ignoredHeaders mudam a cada execução (trace context, transporte) e não são gravados
+var ignoredHeaders = []string{"Traceparent", "Tracestate", "Baggage", "B3", "X-B3-Traceid", "X-B3-Spanid", "X-B3-Sampled", "Accept-Encoding", "User-Agent", "Date", "Content-Length"}
+
+// ErrNoInteraction indica que o golden file não tem resposta para a requisição
+var ErrNoInteraction = errors.New("replay: no recorded interaction")
+
+// ParseMode valida o modo informado em configuração (vazio = ModeOff)
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(value)); mode {
+	case "":
+		return ModeOff, nil
+	case ModeOff, ModeRecord, ModeReplay:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid replay mode %q: expected off, record or replay", value)
+	}
+}
+
+// Request e Response são a forma gravada de uma troca
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette é o conteúdo de um golden file
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Option customiza o Recorder
+type Option func(*Recorder)
+
+// WithRedactedHeaders acrescenta headers cujos valores são removidos na gravação
+func WithRedactedHeaders(headers ...string) Option {
+	return func(r *Recorder) {
+		for _, header := range headers {
+			r.redactedHeaders[http.CanonicalHeaderKey(header)] = true
+		}
+	}
+}
+
+// WithRedactedParams acrescenta parâmetros de query cujos valores são removidos na gravação
+func WithRedactedParams(params ...string) Option {
+	return func(r *Recorder) {
+		for _, param := range params {
+			r.redactedParams[strings.ToLower(param)] = true
+		}
+	}
+}
+
+// Recorder grava ou reproduz as trocas de um golden file; é seguro para uso concorrente
+type Recorder struct {
+	mode            Mode
+	path            string
+	redactedHeaders map[string]bool
+	redactedParams  map[string]bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	// used conta quantas vezes cada interação foi reproduzida
+	used map[int]int
+}
+
+// New cria um Recorder para path. Em ModeReplay o golden file precisa existir;
+// em ModeRecord as novas trocas substituem as gravadas para a mesma requisição.
+func New(mode Mode, path string, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		mode:            mode,
+		path:            path,
+		redactedHeaders: make(map[string]bool),
+		redactedParams:  make(map[string]bool),
+		used:            make(map[int]int),
+	}
+	WithRedactedHeaders(DefaultRedactedHeaders...)(r)
+	WithRedactedParams(DefaultRedactedParams...)(r)
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if mode == ModeOff {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("error parsing golden file %s: %w", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && mode == ModeRecord:
+	default:
+		return nil, fmt.Errorf("error reading golden file: %w", err)
+	}
+	return r, nil
+}
+
+// Wrap devolve um RoundTripper que grava ou reproduz via o Recorder; em ModeOff devolve next
+func (r *Recorder) Wrap(next http.RoundTripper) http.RoundTripper {
+	if r == nil || r.mode == ModeOff {
+		return next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripper{recorder: r, next: next}
+}
+
+type roundTripper struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+func (t roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := t.recorder.request(req, body)
+
+	if t.recorder.mode == ModeReplay {
+		return t.recorder.replay(req, recorded)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("replay: error reading response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	if err := t.recorder.record(Interaction{
+		Request: recorded,
+		Response: Response{
+			Status: resp.StatusCode,
+			Header: t.recorder.header(resp.Header),
+			Body:   string(respBody),
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// readBody lê o corpo da requisição e o restaura para o transport seguinte
+func readBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("replay: error reading request: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return string(body), nil
+}
+
+// request monta a forma gravada da requisição. A URL guarda só path e query
+// (redigida e ordenada), para o golden file valer com qualquer base URL.
+func (r *Recorder) request(req *http.Request, body string) Request {
+	query := req.URL.Query()
+	for param := range query {
+		if r.redactedParams[strings.ToLower(param)] {
+			query[param] = []string{Redacted}
+		}
+	}
+	target := url.URL{Path: req.URL.Path, RawQuery: query.Encode()}
+
+	return Request{
+		Method: req.Method,
+		URL:    target.String(),
+		Header: r.header(req.Header),
+		Body:   body,
+	}
+}
+
+func (r *Recorder) header(header http.Header) http.Header {
+	recorded := make(http.Header)
+	for name, values := range header {
+		name = http.CanonicalHeaderKey(name)
+		switch {
+		case contains(ignoredHeaders, name):
+		case r.redactedHeaders[name]:
+			recorded[name] = []string{Redacted}
+		default:
+			recorded[name] = values
+		}
+	}
+	if len(recorded) == 0 {
+		return nil
+	}
+	return recorded
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+func (i Interaction) matches(req Request) bool {
+	return i.Request.Method == req.Method && i.Request.URL == req.URL && i.Request.Body == req.Body
+}
+
+// record substitui a troca gravada para a mesma requisição e reescreve o golden file
+func (r *Recorder) record(interaction Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	replaced := false
+	for i, existing := range r.cassette.Interactions {
+		if existing.matches(interaction.Request) {
+			r.cassette.Interactions[i] = interaction
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	}
+	sort.SliceStable(r.cassette.Interactions, func(i, j int) bool {
+		return r.cassette.Interactions[i].Request.URL < r.cassette.Interactions[j].Request.URL
+	})
+
+	return r.save()
+}
+
+// save grava em arquivo temporário e renomeia, para nunca deixar um golden file pela metade
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("replay: error encoding golden file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("replay: error creating golden file directory: %w", err)
+	}
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("replay: error writing golden file: %w", err)
+	}
+	return os.Rename(tmp, r.path)
+}
+
+// replay responde com a troca gravada para a requisição; repetições da mesma
+// requisição reutilizam a troca
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if !interaction.matches(recorded) {
+			continue
+		}
+		r.used[i]++
+
+		header := make(http.Header)
+		for name, values := range interaction.Response.Header {
+			header[name] = append([]string(nil), values...)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w for %s %s in %s", ErrNoInteraction, recorded.Method, recorded.URL, r.path)
+}
+
+// Unused lista as trocas gravadas que nenhuma requisição reproduziu (golden file desatualizado)
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] == 0 {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
@@ -0,0 +1,161 @@
+package replay
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte(`{"q":"` + r.URL.Query().Get("q") + `","body":"` + string(body) + `"}`))
+	}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), "testdata", "upstream.json")
+
+	recorder, err := New(ModeRecord, path)
+	require.NoError(t, err)
+	client := &http.Client{Transport: recorder.Wrap(http.DefaultTransport)}
+
+	req, _ := http.NewRequest("POST", upstream.URL+"/current.json?q=Rio&key=super-secret", strings.NewReader("payload"))
+	req.Header.Set("Authorization", "Bearer super-secret")
+	req.Header.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, `{"q":"Rio","body":"payload"}`, string(body))
+	assert.Equal(t, 1, calls)
+
+	// O golden file não guarda segredos nem headers que mudam a cada execução
+	golden, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(golden), "super-secret")
+	assert.NotContains(t, string(golden), "session=secret")
+	assert.NotContains(t, string(golden), "Traceparent")
+	assert.Contains(t, string(golden), Redacted)
+
+	// A reprodução não acessa a rede, aceita qualquer host e chave e repete a resposta
+	replayer, err := New(ModeReplay, path)
+	require.NoError(t, err)
+	client = &http.Client{Transport: replayer.Wrap(http.DefaultTransport)}
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("POST", "http://replay.invalid/current.json?key=other&q=Rio", strings.NewReader("payload"))
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Equal(t, `{"q":"Rio","body":"payload"}`, string(body))
+	}
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, replayer.Unused())
+
+	// Requisições sem troca gravada falham com ErrNoInteraction
+	req, _ = http.NewRequest("POST", "http://replay.invalid/current.json?q=Recife", strings.NewReader("payload"))
+	_, err = replayer.Wrap(nil).RoundTrip(req)
+	assert.ErrorIs(t, err, ErrNoInteraction)
+}
+
+func TestRecordReplacesInteraction(t *testing.T) {
+	status := http.StatusOK
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), "upstream.json")
+	for _, s := range []int{http.StatusOK, http.StatusBadRequest} {
+		status = s
+		recorder, err := New(ModeRecord, path)
+		require.NoError(t, err)
+		resp, err := recorder.Wrap(nil).RoundTrip(httptest.NewRequest("GET", upstream.URL+"/ws/01310-100/json/", nil))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	replayer, err := New(ModeReplay, path)
+	require.NoError(t, err)
+	require.Len(t, replayer.cassette.Interactions, 1)
+	assert.Equal(t, http.StatusBadRequest, replayer.cassette.Interactions[0].Response.Status)
+	assert.Len(t, replayer.Unused(), 1)
+}
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte("{"), 0o644))
+
+	tests := []struct {
+		name        string
+		mode        Mode
+		path        string
+		expectedErr string
+	}{
+		{name: "off ignores file", mode: ModeOff, path: filepath.Join(dir, "missing.json")},
+		{name: "record creates file", mode: ModeRecord, path: filepath.Join(dir, "missing.json")},
+		{name: "replay requires file", mode: ModeReplay, path: filepath.Join(dir, "missing.json"), expectedErr: "error reading golden file"},
+		{name: "invalid golden file", mode: ModeReplay, path: invalid, expectedErr: "error parsing golden file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.mode, tt.path)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    Mode
+		expectedErr bool
+	}{
+		{value: "", expected: ModeOff},
+		{value: "off", expected: ModeOff},
+		{value: "RECORD", expected: ModeRecord},
+		{value: "replay", expected: ModeReplay},
+		{value: "live", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			mode, err := ParseMode(tt.value)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, mode)
+		})
+	}
+}
+
+func TestWrapOff(t *testing.T) {
+	recorder, err := New(ModeOff, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.DefaultTransport, recorder.Wrap(http.DefaultTransport))
+
+	var nilRecorder *Recorder
+	assert.Equal(t, http.DefaultTransport, nilRecorder.Wrap(http.DefaultTransport))
+}
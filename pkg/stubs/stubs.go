@@ -0,0 +1,309 @@
+// Package stubs emula os endpoints do ViaCEP (/ws/{cep}/json/) e da WeatherAPI
+// (/v1/current.json) a partir de fixtures, com latência, erros e comportamento
+// para CEPs desconhecidos configuráveis, para rodar a stack sem internet.
+package stubs
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultFixtures são as fixtures embutidas, usadas quando nenhum arquivo é informado
+//
+//go:embed fixtures.json
+var DefaultFixtures []byte
+
+// Address segue o formato de resposta do ViaCEP
+type Address struct {
+	CEP         string `json:"cep"`
+	Logradouro  string `json:"logradouro"`
+	Complemento string `json:"complemento"`
+	Bairro      string `json:"bairro"`
+	Localidade  string `json:"localidade"`
+	UF          string `json:"uf"`
+	IBGE        string `json:"ibge"`
+	GIA         string `json:"gia"`
+	DDD         string `json:"ddd"`
+	SIAFI       string `json:"siafi"`
+}
+
+// Fixtures mapeia CEPs (8 dígitos) para endereços e cidades para temperaturas em Celsius
+type Fixtures struct {
+	Zipcodes     map[string]Address `json:"zipcodes"`
+	Temperatures map[string]float64 `json:"temperatures"`
+	// DefaultZipcode responde pelos CEPs desconhecidos no modo UnknownDefault
+	DefaultZipcode string `json:"default_zipcode"`
+}
+
+// LoadFixtures lê fixtures em JSON
+func LoadFixtures(data []byte) (*Fixtures, error) {
+	var fixtures Fixtures
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("error parsing fixtures: %w", err)
+	}
+	if fixtures.DefaultZipcode != "" {
+		if _, ok := fixtures.Zipcodes[fixtures.DefaultZipcode]; !ok {
+			return nil, fmt.Errorf("default_zipcode %s is not in zipcodes", fixtures.DefaultZipcode)
+		}
+	}
+	return &fixtures, nil
+}
+
+// LoadFixturesFile lê as fixtures de path ou, se vazio, usa DefaultFixtures
+func LoadFixturesFile(path string) (*Fixtures, error) {
+	if path == "" {
+		return LoadFixtures(DefaultFixtures)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading fixtures: %w", err)
+	}
+	return LoadFixtures(data)
+}
+
+// UnknownZipcode define a resposta do ViaCEP para CEPs válidos fora das fixtures
+type UnknownZipcode string
+
+const (
+	// UnknownNotFound responde {"erro": "true"}, como o ViaCEP real
+	UnknownNotFound UnknownZipcode = "not-found"
+	// UnknownDefault responde com o endereço de DefaultZipcode
+	UnknownDefault UnknownZipcode = "default"
+	// UnknownError responde 500
+	UnknownError UnknownZipcode = "error"
+)
+
+// ParseUnknownZipcode valida o modo informado em configuração
+func ParseUnknownZipcode(value string) (UnknownZipcode, error) {
+	switch mode := UnknownZipcode(value); mode {
+	case UnknownNotFound, UnknownDefault, UnknownError:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid unknown zipcode mode %q: expected not-found, default or error", value)
+	}
+}
+
+// Faults injeta latência e erros nas respostas de um endpoint
+type Faults struct {
+	// Latency é somada a cada resposta, mais um valor aleatório até Jitter
+	Latency time.Duration
+	Jitter  time.Duration
+	// ErrorRate (0 a 1) é a fração de requisições respondidas com ErrorStatus
+	ErrorRate   float64
+	ErrorStatus int
+}
+
+// Option customiza o Server
+type Option func(*Server)
+
+// WithUnknownZipcode define a resposta para CEPs fora das fixtures (padrão UnknownNotFound)
+func WithUnknownZipcode(mode UnknownZipcode) Option {
+	return func(s *Server) {
+		s.unknownZipcode = mode
+	}
+}
+
+// WithViaCEPFaults injeta latência e erros no endpoint do ViaCEP
+func WithViaCEPFaults(faults Faults) Option {
+	return func(s *Server) {
+		s.viacepFaults = faults
+	}
+}
+
+// WithWeatherFaults injeta latência e erros no endpoint da WeatherAPI
+func WithWeatherFaults(faults Faults) Option {
+	return func(s *Server) {
+		s.weatherFaults = faults
+	}
+}
+
+// WithAPIKey exige a chave informada na WeatherAPI (vazio = aceita qualquer chave)
+func WithAPIKey(key string) Option {
+	return func(s *Server) {
+		s.apiKey = key
+	}
+}
+
+// WithRandom substitui a fonte de aleatoriedade de jitter e erros (valores em [0, 1))
+func WithRandom(random func() float64) Option {
+	return func(s *Server) {
+		s.random = random
+	}
+}
+
+// Server responde como o ViaCEP e a WeatherAPI a partir das fixtures
+type Server struct {
+	fixtures       *Fixtures
+	unknownZipcode UnknownZipcode
+	viacepFaults   Faults
+	weatherFaults  Faults
+	apiKey         string
+	random         func() float64
+}
+
+func New(fixtures *Fixtures, opts ...Option) *Server {
+	s := &Server{
+		fixtures:       fixtures,
+		unknownZipcode: UnknownNotFound,
+		random:         rand.Float64,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Handler expõe /ws/{cep}/json/ (VIACEP_BASE_URL=<stub>/ws), /v1/current.json
+// (WEATHER_API_BASE_URL=<stub>/v1) e /healthz
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ws/{cep}/json/", s.viaCEP)
+	mux.HandleFunc("GET /v1/current.json", s.currentWeather)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	return mux
+}
+
+func (s *Server) viaCEP(w http.ResponseWriter, r *http.Request) {
+	if !s.applyFaults(w, r, s.viacepFaults) {
+		return
+	}
+
+	// O cliente envia o CEP formatado (26140-040); o ViaCEP aceita os dois formatos
+	zipcode := strings.ReplaceAll(r.PathValue("cep"), "-", "")
+	if !validZipcode(zipcode) {
+		log.Printf("stub viacep %s: invalid zipcode", zipcode)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	address, ok := s.fixtures.Zipcodes[zipcode]
+	if !ok {
+		switch s.unknownZipcode {
+		case UnknownDefault:
+			address, ok = s.fixtures.Zipcodes[s.fixtures.DefaultZipcode]
+		case UnknownError:
+			log.Printf("stub viacep %s: unknown zipcode, responding 500", zipcode)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+	if !ok {
+		log.Printf("stub viacep %s: not found", zipcode)
+		writeJSON(w, http.StatusOK, map[string]string{"erro": "true"})
+		return
+	}
+
+	log.Printf("stub viacep %s: %s/%s", zipcode, address.Localidade, address.UF)
+	writeJSON(w, http.StatusOK, address)
+}
+
+// weatherError segue o formato de erro da WeatherAPI
+func weatherError(code int, message string) map[string]interface{} {
+	return map[string]interface{}{"error": map[string]interface{}{"code": code, "message": message}}
+}
+
+func (s *Server) currentWeather(w http.ResponseWriter, r *http.Request) {
+	if !s.applyFaults(w, r, s.weatherFaults) {
+		return
+	}
+
+	query := r.URL.Query()
+	key := query.Get("key")
+	switch {
+	case key == "":
+		writeJSON(w, http.StatusUnauthorized, weatherError(1002, "API key is invalid or not provided."))
+		return
+	case s.apiKey != "" && key != s.apiKey:
+		writeJSON(w, http.StatusUnauthorized, weatherError(2006, "API key is invalid."))
+		return
+	}
+
+	// O cliente consulta "Cidade, UF, Brazil"
+	parts := strings.Split(query.Get("q"), ",")
+	city := strings.TrimSpace(parts[0])
+	region := ""
+	if len(parts) > 1 {
+		region = strings.TrimSpace(parts[1])
+	}
+
+	for name, tempC := range s.fixtures.Temperatures {
+		if !strings.EqualFold(name, city) {
+			continue
+		}
+		log.Printf("stub weatherapi %q: %.1f°C", city, tempC)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"location": map[string]string{"name": name, "region": region, "country": "Brazil"},
+			"current":  map[string]float64{"temp_c": tempC, "temp_f": math.Round((tempC*1.8+32)*10) / 10},
+		})
+		return
+	}
+
+	log.Printf("stub weatherapi %q: no matching location", city)
+	writeJSON(w, http.StatusBadRequest, weatherError(1006, "No matching location found."))
+}
+
+// applyFaults aplica a latência e, conforme ErrorRate, responde com ErrorStatus.
+// Retorna false se a resposta já foi escrita.
+func (s *Server) applyFaults(w http.ResponseWriter, r *http.Request, faults Faults) bool {
+	delay := faults.Latency
+	if faults.Jitter > 0 {
+		delay += time.Duration(s.random() * float64(faults.Jitter))
+	}
+	if delay > 0 && !sleep(r.Context(), delay) {
+		return false
+	}
+
+	if faults.ErrorRate > 0 && s.random() < faults.ErrorRate {
+		status := faults.ErrorStatus
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		log.Printf("stub %s: injected status %d", r.URL.Path, status)
+		http.Error(w, http.StatusText(status), status)
+		return false
+	}
+	return true
+}
+
+// sleep espera d ou o cancelamento da requisição (retorna false)
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func validZipcode(zipcode string) bool {
+	if len(zipcode) != 8 {
+		return false
+	}
+	for _, char := range zipcode {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Printf("Error writing stub response: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package stubs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerViaCEP(t *testing.T) {
+	fixtures, err := LoadFixturesFile("")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		opts           []Option
+		path           string
+		expectedStatus int
+		expectedCity   string
+		expectedErro   string
+	}{
+		{name: "formatted zipcode", path: "/ws/26140-040/json/", expectedStatus: http.StatusOK, expectedCity: "Belford Roxo"},
+		{name: "digits only", path: "/ws/01310100/json/", expectedStatus: http.StatusOK, expectedCity: "São Paulo"},
+		{name: "invalid format", path: "/ws/1234/json/", expectedStatus: http.StatusBadRequest},
+		{name: "unknown zipcode not found", path: "/ws/99999-999/json/", expectedStatus: http.StatusOK, expectedErro: "true"},
+		{
+			name:           "unknown zipcode default",
+			opts:           []Option{WithUnknownZipcode(UnknownDefault)},
+			path:           "/ws/99999-999/json/",
+			expectedStatus: http.StatusOK,
+			expectedCity:   "São Paulo",
+		},
+		{
+			name:           "unknown zipcode error",
+			opts:           []Option{WithUnknownZipcode(UnknownError)},
+			path:           "/ws/99999-999/json/",
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "injected error",
+			opts:           []Option{WithViaCEPFaults(Faults{ErrorRate: 1, ErrorStatus: http.StatusServiceUnavailable})},
+			path:           "/ws/26140-040/json/",
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "weather faults do not affect viacep",
+			opts:           []Option{WithWeatherFaults(Faults{ErrorRate: 1})},
+			path:           "/ws/26140-040/json/",
+			expectedStatus: http.StatusOK,
+			expectedCity:   "Belford Roxo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			New(fixtures, tt.opts...).Handler().ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var body struct {
+				Localidade string `json:"localidade"`
+				Erro       string `json:"erro"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedCity, body.Localidade)
+			assert.Equal(t, tt.expectedErro, body.Erro)
+		})
+	}
+}
+
+func TestServerCurrentWeather(t *testing.T) {
+	fixtures, err := LoadFixturesFile("")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		opts           []Option
+		query          string
+		expectedStatus int
+		expectedTempC  float64
+		expectedTempF  float64
+	}{
+		{name: "known city", query: "key=any&q=Belford Roxo, RJ, Brazil&aqi=no", expectedStatus: http.StatusOK, expectedTempC: 31.2, expectedTempF: 88.2},
+		{name: "case insensitive", query: "key=any&q=são paulo", expectedStatus: http.StatusOK, expectedTempC: 22.4, expectedTempF: 72.3},
+		{name: "unknown city", query: "key=any&q=Atlantis, XX, Brazil", expectedStatus: http.StatusBadRequest},
+		{name: "missing key", query: "q=Belford Roxo", expectedStatus: http.StatusUnauthorized},
+		{name: "wrong key", opts: []Option{WithAPIKey("stub")}, query: "key=other&q=Belford Roxo", expectedStatus: http.StatusUnauthorized},
+		{name: "right key", opts: []Option{WithAPIKey("stub")}, query: "key=stub&q=Belford Roxo", expectedStatus: http.StatusOK, expectedTempC: 31.2, expectedTempF: 88.2},
+		{
+			name:           "error rate below draw",
+			opts:           []Option{WithWeatherFaults(Faults{ErrorRate: 0.3}), WithRandom(func() float64 { return 0.5 })},
+			query:          "key=any&q=Belford Roxo",
+			expectedStatus: http.StatusOK,
+			expectedTempC:  31.2,
+			expectedTempF:  88.2,
+		},
+		{
+			name:           "error rate above draw",
+			opts:           []Option{WithWeatherFaults(Faults{ErrorRate: 0.6}), WithRandom(func() float64 { return 0.5 })},
+			query:          "key=any&q=Belford Roxo",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/v1/current.json", nil)
+			req.URL.RawQuery = tt.query
+			rec := httptest.NewRecorder()
+			New(fixtures, tt.opts...).Handler().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var body struct {
+				Current struct {
+					TempC float64 `json:"temp_c"`
+					TempF float64 `json:"temp_f"`
+				} `json:"current"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedTempC, body.Current.TempC)
+			assert.Equal(t, tt.expectedTempF, body.Current.TempF)
+		})
+	}
+}
+
+func TestServerLatency(t *testing.T) {
+	fixtures, err := LoadFixturesFile("")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(New(fixtures,
+		WithViaCEPFaults(Faults{Latency: 50 * time.Millisecond, Jitter: 100 * time.Millisecond}),
+		WithRandom(func() float64 { return 0.5 }),
+	).Handler())
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := http.Get(server.URL + "/ws/26140-040/json/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	// O cancelamento da requisição interrompe a espera
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/ws/26140-040/json/", nil)
+	_, err = http.DefaultClient.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLoadFixtures(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expectedErr string
+	}{
+		{name: "valid", data: `{"zipcodes":{"01310100":{"localidade":"São Paulo"}},"default_zipcode":"01310100"}`},
+		{name: "invalid json", data: `{`, expectedErr: "error parsing fixtures"},
+		{name: "unknown default zipcode", data: `{"zipcodes":{},"default_zipcode":"01310100"}`, expectedErr: "default_zipcode 01310100 is not in zipcodes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFixtures([]byte(tt.data))
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParseUnknownZipcode(t *testing.T) {
+	for _, value := range []string{"not-found", "default", "error"} {
+		mode, err := ParseUnknownZipcode(value)
+		assert.NoError(t, err)
+		assert.Equal(t, UnknownZipcode(value), mode)
+	}
+	_, err := ParseUnknownZipcode("random")
+	assert.Error(t, err)
+}
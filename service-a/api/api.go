@@ -0,0 +1,9 @@
+// Package api contém o contrato OpenAPI do Service A
+package api
+
+import _ "embed"
+
+// OpenAPISpec é a especificação OpenAPI 3 servida em /openapi.json
+//
+//go:embed openapi.json
+var OpenAPISpec []byte
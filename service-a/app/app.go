@@ -0,0 +1,382 @@
+// Package app monta o Service A a partir da configuração: é usado pelo main e
+// pelos testes end-to-end, que sobem os serviços no mesmo processo.
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"log"
+	"net/http"
+	"net/netip"
+	"path/filepath"
+	"strings"
+	"time"
+
+	weatherv1 "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/api/weather/v1"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/compress"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/config"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/deadline"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/decode"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/fault"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/health"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/mtls"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/openapi"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/replay"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/api"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/handler"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/repository"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+)
+
+// App reúne o handler HTTP do Service A e os recursos a liberar no encerramento
+type App struct {
+	// Handler é o roteador com compressão e h2c, pronto para o http.Server
+	Handler http.Handler
+	// TLSConfig é nil sem TLS_CERT_FILE
+	TLSConfig *tls.Config
+
+	closeServiceB func()
+}
+
+// New monta o Service A; os certificados são recarregados do disco até ctx terminar
+func New(ctx context.Context, config *Config) *App {
+	// Configura os clientes; com SERVICE_B_TLS_* as chamadas usam mTLS
+	var (
+		serviceBTLS  *tls.Config
+		healthClient = http.DefaultClient
+	)
+	if certs := loadCertificates(ctx, "SERVICE_B_TLS", config.ServiceB.CertFile, config.ServiceB.KeyFile, config.ServiceB.CAFile, config.TLS.ReloadInterval); certs != nil {
+		serviceBTLS = mtls.ClientConfig(certs, config.ServiceB.ServerName)
+		healthClient = &http.Client{Transport: &http.Transport{TLSClientConfig: serviceBTLS}}
+	}
+	faults := setupFaults(config.Faults)
+	serviceBClient, serviceBCheck, closeServiceB := setupServiceBClient(config, serviceBTLS, healthClient, faults)
+	access := accessMiddlewares(config)
+	weatherHandler := handler.NewWeatherHandler(serviceBClient, handlerOptions(config, access)...)
+
+	// Readiness depende apenas da liveness do Service B, para que uma falha nos
+	// provedores externos não tire os dois serviços do balanceamento em cascata
+	checker := health.NewChecker(config.Health.CacheTTL, config.Health.CheckTimeout)
+	checker.Register("service-b", serviceBCheck)
+
+	// Health checks e documentação são públicos; as demais rotas passam pelo
+	// mesmo controle de acesso de POST /weather, para que AUTH_ROUTE_SCOPES valha
+	// em todas elas
+	router := weatherHandler.SetupRoutes()
+	router.Get("/healthz", health.LivenessHandler())
+	router.Get("/readyz", checker.ReadinessHandler())
+	router.Get("/openapi.json", openapi.SpecHandler(api.OpenAPISpec))
+	router.Get("/docs", openapi.SwaggerUIHandler("Service A", "/openapi.json"))
+
+	protected := router.With(access...)
+	if config.Faults.AdminEnabled {
+		protected.Mount("/admin/faults", http.StripPrefix("/admin/faults", faults.Handler()))
+	}
+
+	a := &App{
+		Handler:       serverHandler(config.HTTP, router),
+		closeServiceB: closeServiceB,
+	}
+
+	// Com TLS_CERT_FILE/TLS_KEY_FILE o servidor aceita apenas HTTPS
+	if certs := loadCertificates(ctx, "TLS", config.TLS.CertFile, config.TLS.KeyFile, config.TLS.CAFile, config.TLS.ReloadInterval); certs != nil {
+		a.TLSConfig = mtls.ServerConfig(certs, config.TLS.AllowedClients)
+	}
+	return a
+}
+
+// Close encerra a conexão com o Service B (gRPC)
+func (a *App) Close() {
+	a.closeServiceB()
+}
+
+// setupServiceBClient escolhe o transporte para o Service B conforme
+// SERVICE_B_TRANSPORT: "http" (padrão, JSON) ou "grpc" (SERVICE_B_GRPC_TARGET).
+// O check de readiness usa o mesmo transporte: GET /healthz ou grpc.health.v1.
+func setupServiceBClient(config *Config, tlsConfig *tls.Config, healthClient *http.Client, faults *fault.Injector) (repository.ServiceBClient, health.CheckFunc, func()) {
+	switch transport := config.ServiceB.Transport; transport {
+	case "http":
+		opts := []repository.ServiceBClientOption{
+			repository.WithMaxIdleConnsPerHost(config.ServiceB.MaxIdleConnsPerHost),
+			repository.WithMaxConnsPerHost(config.ServiceB.MaxConnsPerHost),
+			repository.WithIdleConnTimeout(config.ServiceB.IdleConnTimeout),
+			repository.WithTimeout(config.ServiceB.Timeout),
+			repository.WithTransportWrapper(setupRecorder(config.Replay, "service-b").Wrap),
+			repository.WithTransportWrapper(faults.Wrap("service-b")),
+		}
+		if tlsConfig != nil {
+			opts = append(opts, repository.WithTLSConfig(tlsConfig))
+		} else if config.ServiceB.H2C {
+			log.Println("Calling Service B via h2c")
+			opts = append(opts, repository.WithH2C())
+		}
+		check := health.HTTPCheck(healthClient, config.ServiceB.URL+"/healthz")
+		return repository.NewServiceBClient(config.ServiceB.URL, opts...), check, func() {}
+	case "grpc":
+		conn, err := repository.DialServiceB(config.ServiceB.GRPCTarget, tlsConfig,
+			grpc.WithChainUnaryInterceptor(faults.UnaryClientInterceptor("service-b")),
+		)
+		if err != nil {
+			log.Fatalf("Failed to connect to Service B: %v", err)
+		}
+		log.Printf("Calling Service B via gRPC at %s", config.ServiceB.GRPCTarget)
+		check := health.GRPCCheck(conn, weatherv1.WeatherService_ServiceDesc.ServiceName)
+		return repository.NewServiceBGRPCClient(conn), check, func() { conn.Close() }
+	default:
+		log.Fatalf("Invalid SERVICE_B_TRANSPORT %q: expected http or grpc", transport)
+		return nil, nil, nil
+	}
+}
+
+// setupRecorder grava (HTTP_REPLAY_MODE=record) ou reproduz (replay) as trocas
+// HTTP em HTTP_REPLAY_DIR/<name>.json; com "off" (padrão) não interfere
+func setupRecorder(config config.Replay, name string) *replay.Recorder {
+	mode, err := replay.ParseMode(string(config.Mode))
+	if err != nil {
+		log.Fatalf("Invalid HTTP_REPLAY_MODE: %v", err)
+	}
+
+	path := filepath.Join(config.Dir, name+".json")
+	recorder, err := replay.New(mode, path)
+	if err != nil {
+		log.Fatalf("Failed to set up HTTP replay: %v", err)
+	}
+	if mode != replay.ModeOff {
+		log.Printf("HTTP %s mode for %s (%s)", mode, name, path)
+	}
+	return recorder
+}
+
+// setupFaults lê as falhas a injetar por dependência (service-b, nos dois
+// transportes) de FAULTS, em JSON; com FAULT_ADMIN_ENABLED=true elas podem ser
+// trocadas em /admin/faults (com autenticação e o scope admin) sem reiniciar o serviço
+func setupFaults(config config.Faults) *fault.Injector {
+	rules, err := fault.ParseRules(config.Rules, faultDependencies...)
+	if err != nil {
+		log.Fatalf("Invalid FAULTS: %v", err)
+	}
+
+	injector := fault.New(fault.WithDependencies(faultDependencies...), fault.WithRules(rules))
+	if dependencies := injector.Dependencies(); len(dependencies) > 0 {
+		log.Printf("Warning: injecting faults into %s", strings.Join(dependencies, ", "))
+	}
+	return injector
+}
+
+// serverHandler adiciona a compressão das respostas (COMPRESSION_*) e o
+// suporte a HTTP/2 sem TLS (H2C_ENABLED); com TLS o HTTP/2 vem do ALPN
+func serverHandler(config config.HTTP, router http.Handler) http.Handler {
+	handler := router
+	if config.CompressionEnabled {
+		handler = compress.New(
+			compress.WithMinSize(config.CompressionMinBytes),
+			compress.WithContentTypes(config.CompressionContentTypes...),
+		).Middleware(handler)
+	}
+	if config.H2CEnabled {
+		handler = h2c.NewHandler(handler, &http2.Server{})
+	}
+	return handler
+}
+
+// loadCertificates lê o certificado, a chave e a CA (opcional) de name
+// (ex: SERVICE_B_TLS) e os recarrega quando mudam em disco; retorna nil se não
+// houver certificado
+func loadCertificates(ctx context.Context, name, certFile, keyFile, caFile string, reloadInterval time.Duration) *mtls.Reloader {
+	if certFile == "" {
+		return nil
+	}
+
+	certs, err := mtls.NewReloader(certFile, keyFile, caFile)
+	if err != nil {
+		log.Fatalf("Failed to load %s certificates: %v", name, err)
+	}
+	go certs.Watch(ctx, reloadInterval)
+
+	return certs
+}
+
+// handlerOptions monta os middlewares de POST /weather: prazo da requisição
+// (REQUEST_TIMEOUT), controle de acesso (access) e, com
+// OPENAPI_VALIDATE_RESPONSES, a validação OpenAPI das respostas
+func handlerOptions(config *Config, access []func(http.Handler) http.Handler) []handler.Option {
+	// O prazo (REQUEST_TIMEOUT) vale desde a chegada da requisição e o restante
+	// é repassado ao Service B em X-Request-Timeout
+	middlewares := []func(http.Handler) http.Handler{deadline.Middleware(config.Server.RequestTimeout, config.Server.MaxRequestTimeout())}
+	middlewares = append(middlewares, access...)
+
+	// Validação por último: respostas de requisições rejeitadas antes não são bufferizadas
+	if config.HTTP.OpenAPIValidateResponses {
+		middlewares = append(middlewares, setupValidator().Middleware)
+	}
+
+	decoder := decode.New(
+		decode.WithMaxBytes(config.HTTP.MaxBodyBytes),
+		decode.WithUnknownFields(config.HTTP.JSONAllowUnknownFields),
+	)
+
+	return []handler.Option{
+		handler.WithMiddleware(middlewares...),
+		handler.WithDecoder(decoder),
+		handler.WithTrustedProxies(trustedProxies(config.TrustedProxies)...),
+	}
+}
+
+// trustedProxies converte TRUSTED_PROXIES, já validado em Config.Validate
+func trustedProxies(values []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes
+}
+
+// accessMiddlewares monta o controle de acesso das rotas não públicas:
+// autenticação por API key (API_KEYS_FILE) e/ou JWT (JWT_ISSUER), autorização
+// por scopes (AUTH_ROUTE_SCOPES) e rate limiting (RATE_LIMIT_*, por IP antes da
+// autenticação e por cliente depois)
+func accessMiddlewares(config *Config) []func(http.Handler) http.Handler {
+	var middlewares []func(http.Handler) http.Handler
+
+	// Os limites por IP e global vêm antes da autenticação, para contar também as
+	// requisições recusadas; o limite por cliente autenticado vem depois dela
+	limiter := setupRateLimiter(config.RateLimit)
+	if limiter != nil {
+		middlewares = append(middlewares, limiter.IPMiddleware)
+	}
+
+	var keyStore repository.APIKeyStore
+	if path := config.Auth.APIKeysFile; path != "" {
+		var err error
+		if keyStore, err = repository.NewFileAPIKeyStore(path); err != nil {
+			log.Fatalf("Failed to load API keys: %v", err)
+		}
+	}
+
+	var authOptions []handler.AuthenticatorOption
+	if verifier := setupTokenVerifier(config.Auth); verifier != nil {
+		authOptions = append(authOptions, handler.WithTokenVerifier(verifier))
+	}
+
+	if keyStore != nil || len(authOptions) > 0 {
+		quotaStore := repository.NewInMemoryQuotaStore(config.Auth.QuotaPeriod)
+		middlewares = append(middlewares, handler.NewAuthenticator(keyStore, quotaStore, authOptions...).Middleware)
+
+		// As rotas administrativas nunca ficam abertas a qualquer cliente autenticado
+		routes := parseRouteScopes(config.Auth.RouteScopes)
+		if _, ok := routes[adminRoutes]; !ok {
+			routes[adminRoutes] = []string{adminScope}
+		}
+		middlewares = append(middlewares, handler.NewScopeAuthorizer(routes).Middleware)
+		if limiter != nil {
+			middlewares = append(middlewares, limiter.ClientMiddleware)
+		}
+	} else {
+		log.Println("Warning: API_KEYS_FILE and JWT_ISSUER not configured, accepting anonymous requests")
+	}
+
+	return middlewares
+}
+
+// setupTokenVerifier habilita JWTs quando JWT_ISSUER está definido; sem
+// JWT_JWKS_URL o endpoint de chaves é obtido via descoberta OIDC
+func setupTokenVerifier(config AuthConfig) handler.TokenVerifier {
+	issuer := config.JWTIssuer
+	if issuer == "" {
+		return nil
+	}
+
+	jwksURL := config.JWTJWKSURL
+	if jwksURL == "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var err error
+		if jwksURL, err = repository.DiscoverJWKSURL(ctx, issuer); err != nil {
+			log.Fatalf("Failed to discover JWKS URL: %v", err)
+		}
+	}
+
+	keys := repository.NewJWKSClient(jwksURL, config.JWTJWKSCacheTTL, config.JWTJWKSMinRefresh)
+	return handler.NewJWTVerifier(keys, issuer, config.JWTAudience)
+}
+
+// adminRoutes exige adminScope quando AUTH_ROUTE_SCOPES não define outro
+const (
+	adminRoutes = "/admin/*"
+	adminScope  = "admin"
+)
+
+// parseRouteScopes lê AUTH_ROUTE_SCOPES=/weather=weather:read,/admin/*=admin
+// (scopes de uma mesma rota separados por espaço)
+func parseRouteScopes(value string) map[string][]string {
+	routes := make(map[string][]string)
+	for _, entry := range strings.Split(value, ",") {
+		route, scopes, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		routes[route] = strings.Fields(scopes)
+	}
+	return routes
+}
+
+// setupValidator valida as respostas contra api/openapi.json
+// (OPENAPI_VALIDATE_RESPONSES, testes/desenvolvimento); o body das requisições
+// fica com o decoder do handler (pkg/decode)
+func setupValidator() *openapi.Validator {
+	validator, err := openapi.NewValidator(api.OpenAPISpec)
+	if err != nil {
+		log.Fatalf("Failed to load OpenAPI spec: %v", err)
+	}
+	return validator
+}
+
+func setupRateLimiter(config RateLimitConfig) *handler.RateLimiter {
+	var (
+		rateLimitConfig handler.RateLimitConfig
+		err             error
+	)
+	if rateLimitConfig.Global, err = domain.ParseRateLimit(config.Global); err != nil {
+		log.Fatalf("Invalid RATE_LIMIT_GLOBAL: %v", err)
+	}
+	if rateLimitConfig.PerClient, err = domain.ParseRateLimit(config.Client); err != nil {
+		log.Fatalf("Invalid RATE_LIMIT_CLIENT: %v", err)
+	}
+
+	// RATE_LIMIT_ROUTES=/weather=5/s,/other=10/m
+	rateLimitConfig.Routes = make(map[string]domain.RateLimit)
+	for _, entry := range strings.Split(config.Routes, ",") {
+		route, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		if rateLimitConfig.Routes[route], err = domain.ParseRateLimit(value); err != nil {
+			log.Fatalf("Invalid RATE_LIMIT_ROUTES entry for %s: %v", route, err)
+		}
+	}
+
+	if !rateLimitConfig.Global.Enabled() && !rateLimitConfig.PerClient.Enabled() && len(rateLimitConfig.Routes) == 0 {
+		return nil
+	}
+
+	// Com RATE_LIMIT_REDIS_URL os buckets são compartilhados entre as réplicas
+	store := repository.NewInMemoryRateLimitStore()
+	if redisURL := config.RedisURL; redisURL != "" {
+		options, err := redis.ParseURL(redisURL)
+		if err != nil {
+			log.Fatalf("Invalid RATE_LIMIT_REDIS_URL: %v", err)
+		}
+		store = repository.NewRedisRateLimitStore(redis.NewClient(options), "service-a:ratelimit:")
+	}
+
+	return handler.NewRateLimiter(store, rateLimitConfig)
+}
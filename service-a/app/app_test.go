@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// writeAPIKeys grava um API_KEYS_FILE com os scopes de cada chave
+func writeAPIKeys(t *testing.T, scopes map[string][]string) string {
+	t.Helper()
+
+	var entries []map[string]interface{}
+	for key, keyScopes := range scopes {
+		sum := sha256.Sum256([]byte(key))
+		entries = append(entries, map[string]interface{}{
+			"client_id":  key,
+			"key_sha256": hex.EncodeToString(sum[:]),
+			"scopes":     keyScopes,
+		})
+	}
+	data, err := json.Marshal(entries)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "api-keys.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestNewAppliesAccessControlToEveryRoute(t *testing.T) {
+	v := NewConfig()
+	v.Set("api_keys_file", writeAPIKeys(t, map[string][]string{
+		"admin-key":  {"admin"},
+		"reader-key": {"weather:read"},
+	}))
+	// Sem entrada para /admin/* em AUTH_ROUTE_SCOPES, vale o scope admin
+	v.Set("auth_route_scopes", "/weather=weather:read")
+	v.Set("fault_admin_enabled", true)
+	cfg, err := LoadConfig(v)
+	require.NoError(t, err)
+
+	a := New(context.Background(), cfg)
+	defer a.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		key      string
+		expected int
+	}{
+		{name: "admin without credentials", path: "/admin/faults", expected: http.StatusUnauthorized},
+		{name: "admin without scope", path: "/admin/faults", key: "reader-key", expected: http.StatusForbidden},
+		{name: "admin with scope", path: "/admin/faults", key: "admin-key", expected: http.StatusOK},
+		{name: "health checks stay public", path: "/healthz", expected: http.StatusOK},
+		{name: "docs stay public", path: "/openapi.json", expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			rec := httptest.NewRecorder()
+			a.Handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expected, rec.Code)
+		})
+	}
+}
+
+func TestNewRequestBodyErrors(t *testing.T) {
+	cfg, err := LoadConfig(NewConfig())
+	require.NoError(t, err)
+
+	a := New(context.Background(), cfg)
+	defer a.Close()
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "malformed json", body: `{"cep":`, expectedStatus: http.StatusBadRequest, expectedBody: `{"message":"invalid request body"}`},
+		{name: "non-string cep", body: `{"cep":26140040}`, expectedStatus: http.StatusBadRequest, expectedBody: `{"message":"invalid request body","errors":[{"field":"cep","message":"must be a string"}]}`},
+		{name: "missing cep", body: `{}`, expectedStatus: http.StatusUnprocessableEntity, expectedBody: `{"message":"invalid zipcode","errors":[{"field":"cep","message":"is required"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/weather", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			a.Handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestNewReadinessChecksServiceBOverGRPC(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         healthpb.HealthCheckResponse_ServingStatus
+		expectedStatus int
+	}{
+		{name: "serving", status: healthpb.HealthCheckResponse_SERVING, expectedStatus: http.StatusOK},
+		{name: "not serving", status: healthpb.HealthCheckResponse_NOT_SERVING, expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			healthServer := grpchealth.NewServer()
+			healthServer.SetServingStatus("weather.v1.WeatherService", tt.status)
+			server := grpc.NewServer()
+			healthpb.RegisterHealthServer(server, healthServer)
+			go server.Serve(listener)
+			defer server.Stop()
+
+			// SERVICE_B_URL fica no padrão: o check precisa seguir o transporte gRPC
+			config := NewConfig()
+			config.Set("service_b_transport", "grpc")
+			config.Set("service_b_grpc_target", listener.Addr().String())
+			cfg, err := LoadConfig(config)
+			require.NoError(t, err)
+
+			a := New(context.Background(), cfg)
+			defer a.Close()
+
+			rec := httptest.NewRecorder()
+			a.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			assert.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
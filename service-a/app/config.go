@@ -0,0 +1,228 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/compress"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/config"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/decode"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/replay"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/repository"
+	"github.com/spf13/viper"
+)
+
+// Config é a configuração tipada do Service A; as tags são os nomes das
+// variáveis de ambiente em minúsculas
+type Config struct {
+	Port int `mapstructure:"port"`
+	// TrustedProxies são os blocos CIDR dos proxies cujos X-Forwarded-For e
+	// X-Real-IP identificam o cliente (log e rate limiting por IP); vazio =
+	// headers ignorados, vale o endereço da conexão
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+
+	ServiceB  ServiceBConfig  `mapstructure:",squash"`
+	Auth      AuthConfig      `mapstructure:",squash"`
+	RateLimit RateLimitConfig `mapstructure:",squash"`
+
+	Server    config.Server    `mapstructure:",squash"`
+	TLS       config.TLS       `mapstructure:",squash"`
+	Replay    config.Replay    `mapstructure:",squash"`
+	Faults    config.Faults    `mapstructure:",squash"`
+	HTTP      config.HTTP      `mapstructure:",squash"`
+	Health    config.Health    `mapstructure:",squash"`
+	Telemetry config.Telemetry `mapstructure:",squash"`
+}
+
+// ServiceBConfig é a conexão com o Service B (SERVICE_B_*)
+type ServiceBConfig struct {
+	URL string `mapstructure:"service_b_url"`
+	// Transport é "http" (JSON em URL) ou "grpc" (GRPCTarget)
+	Transport           string        `mapstructure:"service_b_transport"`
+	GRPCTarget          string        `mapstructure:"service_b_grpc_target"`
+	CertFile            string        `mapstructure:"service_b_tls_cert_file"`
+	KeyFile             string        `mapstructure:"service_b_tls_key_file"`
+	CAFile              string        `mapstructure:"service_b_tls_ca_file"`
+	ServerName          string        `mapstructure:"service_b_tls_server_name"`
+	H2C                 bool          `mapstructure:"service_b_h2c"`
+	MaxIdleConnsPerHost int           `mapstructure:"service_b_max_idle_conns_per_host"`
+	MaxConnsPerHost     int           `mapstructure:"service_b_max_conns_per_host"`
+	IdleConnTimeout     time.Duration `mapstructure:"service_b_idle_conn_timeout"`
+	// Timeout limita cada chamada (HTTP), junto com o prazo da requisição
+	Timeout time.Duration `mapstructure:"service_b_timeout"`
+}
+
+// AuthConfig é a autenticação por API key e/ou JWT e a autorização por scopes
+type AuthConfig struct {
+	APIKeysFile       string        `mapstructure:"api_keys_file"`
+	QuotaPeriod       time.Duration `mapstructure:"quota_period"`
+	JWTIssuer         string        `mapstructure:"jwt_issuer"`
+	JWTAudience       string        `mapstructure:"jwt_audience"`
+	JWTJWKSURL        string        `mapstructure:"jwt_jwks_url"`
+	JWTJWKSCacheTTL   time.Duration `mapstructure:"jwt_jwks_cache_ttl"`
+	JWTJWKSMinRefresh time.Duration `mapstructure:"jwt_jwks_min_refresh"`
+	// RouteScopes é /weather=weather:read,/admin/*=admin; sem entrada para
+	// /admin/*, essas rotas exigem o scope admin
+	RouteScopes string `mapstructure:"auth_route_scopes"`
+}
+
+// RateLimitConfig são os limites no formato "5/s" (RATE_LIMIT_*)
+type RateLimitConfig struct {
+	Global string `mapstructure:"rate_limit_global"`
+	Client string `mapstructure:"rate_limit_client"`
+	// Routes é /weather=5/s,/other=10/m
+	Routes   string `mapstructure:"rate_limit_routes"`
+	RedisURL string `mapstructure:"rate_limit_redis_url"`
+}
+
+// faultDependencies são as dependências em que FAULTS injeta falhas: o cliente
+// do Service B, por HTTP ou gRPC
+var faultDependencies = []string{"service-b"}
+
+// Validate reúne todos os problemas da configuração
+func (c *Config) Validate(v *config.Validator) {
+	v.Port("PORT", c.Port)
+	v.CIDRs("TRUSTED_PROXIES", c.TrustedProxies)
+	c.ServiceB.Validate(v)
+	c.Auth.Validate(v)
+	c.RateLimit.Validate(v)
+	// /admin/faults exige o scope admin, então só existe com autenticação
+	if c.Faults.AdminEnabled && c.Auth.APIKeysFile == "" && c.Auth.JWTIssuer == "" {
+		v.Addf("FAULT_ADMIN_ENABLED", "requires API_KEYS_FILE or JWT_ISSUER")
+	}
+
+	c.Server.Validate(v)
+	c.TLS.Validate(v)
+	c.Replay.Validate(v)
+	c.Faults.Validate(v, faultDependencies...)
+	c.HTTP.Validate(v)
+	c.Health.Validate(v)
+	c.Telemetry.Validate(v)
+}
+
+func (c ServiceBConfig) Validate(v *config.Validator) {
+	v.OneOf("SERVICE_B_TRANSPORT", c.Transport, "http", "grpc")
+	switch c.Transport {
+	case "http":
+		v.Required("SERVICE_B_URL", c.URL)
+		v.URL("SERVICE_B_URL", c.URL)
+	case "grpc":
+		v.HostPort("SERVICE_B_GRPC_TARGET", c.GRPCTarget)
+	}
+
+	v.Together([]string{"SERVICE_B_TLS_CERT_FILE", "SERVICE_B_TLS_KEY_FILE"}, c.CertFile, c.KeyFile)
+	v.File("SERVICE_B_TLS_CERT_FILE", c.CertFile)
+	v.File("SERVICE_B_TLS_KEY_FILE", c.KeyFile)
+	v.File("SERVICE_B_TLS_CA_FILE", c.CAFile)
+	v.Min("SERVICE_B_MAX_IDLE_CONNS_PER_HOST", int64(c.MaxIdleConnsPerHost), 0)
+	v.Min("SERVICE_B_MAX_CONNS_PER_HOST", int64(c.MaxConnsPerHost), 0)
+	v.NonNegative("SERVICE_B_IDLE_CONN_TIMEOUT", c.IdleConnTimeout)
+	v.Positive("SERVICE_B_TIMEOUT", c.Timeout)
+}
+
+func (c AuthConfig) Validate(v *config.Validator) {
+	v.File("API_KEYS_FILE", c.APIKeysFile)
+	v.Positive("QUOTA_PERIOD", c.QuotaPeriod)
+	v.URL("JWT_ISSUER", c.JWTIssuer)
+	v.URL("JWT_JWKS_URL", c.JWTJWKSURL)
+	if c.JWTIssuer != "" {
+		v.Positive("JWT_JWKS_CACHE_TTL", c.JWTJWKSCacheTTL)
+		v.NonNegative("JWT_JWKS_MIN_REFRESH", c.JWTJWKSMinRefresh)
+	}
+	for _, entry := range strings.Split(c.RouteScopes, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" && !strings.Contains(entry, "=") {
+			v.Addf("AUTH_ROUTE_SCOPES", "entry %q must be route=scopes", entry)
+		}
+	}
+}
+
+func (c RateLimitConfig) Validate(v *config.Validator) {
+	_, err := domain.ParseRateLimit(c.Global)
+	v.Check("RATE_LIMIT_GLOBAL", err)
+	_, err = domain.ParseRateLimit(c.Client)
+	v.Check("RATE_LIMIT_CLIENT", err)
+	for _, entry := range strings.Split(c.Routes, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		route, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			v.Addf("RATE_LIMIT_ROUTES", "entry %q must be route=limit", entry)
+			continue
+		}
+		if _, err := domain.ParseRateLimit(value); err != nil {
+			v.Addf("RATE_LIMIT_ROUTES", "%s: %v", route, err)
+		}
+	}
+	v.URL("RATE_LIMIT_REDIS_URL", c.RedisURL, "redis", "rediss")
+}
+
+// LoadConfig lê e valida a configuração; o erro (*config.Error) lista todos
+// os problemas encontrados
+func LoadConfig(v *viper.Viper) (*Config, error) {
+	cfg := &Config{}
+	return cfg, config.Load(v, cfg)
+}
+
+// NewConfig cria a configuração com os valores padrão, sobrescritos pelas
+// variáveis de ambiente
+func NewConfig() *viper.Viper {
+	v := viper.New()
+
+	v.SetDefault("port", 8080)
+	v.SetDefault("service_b_url", "http://localhost:8081")
+	v.SetDefault("service_b_transport", "http")
+	v.SetDefault("service_b_grpc_target", "localhost:9091")
+	v.SetDefault("tls_cert_file", "")
+	v.SetDefault("tls_key_file", "")
+	v.SetDefault("tls_ca_file", "")
+	v.SetDefault("tls_allowed_clients", "")
+	v.SetDefault("tls_reload_interval", "30s")
+	v.SetDefault("service_b_tls_cert_file", "")
+	v.SetDefault("service_b_tls_key_file", "")
+	v.SetDefault("service_b_tls_ca_file", "")
+	v.SetDefault("service_b_tls_server_name", "")
+	v.SetDefault("service_b_h2c", false)
+	v.SetDefault("service_b_max_idle_conns_per_host", repository.DefaultMaxIdleConnsPerHost)
+	v.SetDefault("service_b_max_conns_per_host", 0)
+	v.SetDefault("service_b_idle_conn_timeout", repository.DefaultIdleConnTimeout)
+	v.SetDefault("service_b_timeout", repository.DefaultTimeout)
+	v.SetDefault("server_read_timeout", "10s")
+	v.SetDefault("server_write_timeout", "10s")
+	v.SetDefault("server_shutdown_timeout", "30s")
+	v.SetDefault("request_timeout", "9s")
+	v.SetDefault("http_replay_mode", string(replay.ModeOff))
+	v.SetDefault("http_replay_dir", "testdata/replay")
+	v.SetDefault("faults", "")
+	v.SetDefault("fault_admin_enabled", false)
+	v.SetDefault("api_keys_file", "")
+	v.SetDefault("quota_period", "24h")
+	v.SetDefault("jwt_issuer", "")
+	v.SetDefault("jwt_audience", "service-a")
+	v.SetDefault("jwt_jwks_url", "")
+	v.SetDefault("jwt_jwks_cache_ttl", "1h")
+	v.SetDefault("jwt_jwks_min_refresh", "1m")
+	v.SetDefault("auth_route_scopes", "")
+	v.SetDefault("trusted_proxies", "")
+	v.SetDefault("rate_limit_global", "")
+	v.SetDefault("rate_limit_client", "")
+	v.SetDefault("rate_limit_routes", "")
+	v.SetDefault("rate_limit_redis_url", "")
+	v.SetDefault("max_body_bytes", decode.DefaultMaxBytes)
+	v.SetDefault("json_allow_unknown_fields", false)
+	v.SetDefault("openapi_validate_responses", false)
+	v.SetDefault("compression_enabled", true)
+	v.SetDefault("compression_min_bytes", compress.DefaultMinSize)
+	v.SetDefault("compression_content_types", strings.Join(compress.DefaultContentTypes, ","))
+	v.SetDefault("h2c_enabled", true)
+	v.SetDefault("health_cache_ttl", "10s")
+	v.SetDefault("health_check_timeout", "2s")
+	v.SetDefault("zipkin_url", "")
+	v.SetDefault("otel_propagators", "")
+	v.SetDefault("deployment_environment", "")
+
+	v.AutomaticEnv()
+
+	return v
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name             string
+		values           map[string]interface{}
+		expectedProblems []string
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name: "grpc transport ignores service b url",
+			values: map[string]interface{}{
+				"service_b_transport":   "grpc",
+				"service_b_url":         "",
+				"service_b_grpc_target": "service-b:9091",
+			},
+		},
+		{
+			name: "lists every problem",
+			values: map[string]interface{}{
+				"port":                 70000,
+				"service_b_url":        "service-b:8081",
+				"tls_cert_file":        "testdata/missing.pem",
+				"rate_limit_client":    "5 per second",
+				"rate_limit_routes":    "/weather=5/s,/other",
+				"rate_limit_redis_url": "http://redis:6379",
+				"quota_period":         "0s",
+				"http_replay_mode":     "rewind",
+			},
+			expectedProblems: []string{
+				"PORT: must be between 1 and 65535, got 70000",
+				`SERVICE_B_URL: must be a http or https URL with host, got "service-b:8081"`,
+				"QUOTA_PERIOD: must be a positive duration, got 0s",
+				`RATE_LIMIT_CLIENT: invalid rate limit "5 per second": expected <requests>/<period>`,
+				`RATE_LIMIT_ROUTES: entry "/other" must be route=limit`,
+				`RATE_LIMIT_REDIS_URL: must be a redis or rediss URL with host, got "http://redis:6379"`,
+				"TLS_CERT_FILE, TLS_KEY_FILE: must be set together",
+				"TLS_CERT_FILE: stat testdata/missing.pem: no such file or directory",
+				`HTTP_REPLAY_MODE: invalid replay mode "rewind": expected off, record or replay`,
+			},
+		},
+		{
+			name:             "fault admin requires authentication",
+			values:           map[string]interface{}{"fault_admin_enabled": true},
+			expectedProblems: []string{"FAULT_ADMIN_ENABLED: requires API_KEYS_FILE or JWT_ISSUER"},
+		},
+		{
+			name:             "fault rule for unknown dependency",
+			values:           map[string]interface{}{"faults": `{"service-c":{"error_rate":1}}`},
+			expectedProblems: []string{`FAULTS: invalid fault rules: unknown dependency "service-c" (expected service-b)`},
+		},
+		{
+			name:             "invalid transport",
+			values:           map[string]interface{}{"service_b_transport": "udp", "service_b_grpc_target": "service-b"},
+			expectedProblems: []string{`SERVICE_B_TRANSPORT: must be one of http, grpc, got "udp"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewConfig()
+			for key, value := range tt.values {
+				v.Set(key, value)
+			}
+
+			cfg, err := LoadConfig(v)
+			if tt.expectedProblems != nil {
+				var configErr *config.Error
+				require.True(t, errors.As(err, &configErr), err)
+				assert.Equal(t, tt.expectedProblems, configErr.Problems)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 8080, cfg.Port)
+		})
+	}
+}
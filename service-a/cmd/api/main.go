@@ -2,46 +2,78 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/config"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel"
-	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/handler"
-	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/repository"
-	"github.com/spf13/viper"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/app"
+)
+
+// Injetados em tempo de build via -ldflags "-X main.version=... -X main.commit=..."
+var (
+	version = "dev"
+	commit  = "unknown"
 )
 
 func main() {
-	config := setupConfig()
+	printConfig := flag.Bool("print-config", false, "print the effective configuration (secrets masked) and exit")
+	flag.Parse()
+
+	cfg, err := setupConfig()
+	if *printConfig {
+		// Imprime o que foi possível ler mesmo com erros, que vão para o stderr
+		if cfg != nil {
+			config.Print(os.Stdout, cfg)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	// Inicializa o OpenTelemetry
-	shutdown, err := otel.InitTracer("service-a", config.GetString("zipkin_url"))
+	// Inicializa o OpenTelemetry (variáveis OTEL_* padrão, sobrescritas pelo .env)
+	shutdown, err := otel.InitTracer("service-a",
+		otel.WithZipkinEndpoint(cfg.Telemetry.ZipkinURL),
+		otel.WithPropagators(cfg.Telemetry.Propagators...),
+		otel.WithVersion(version, commit),
+		otel.WithEnvironment(cfg.Telemetry.Environment),
+	)
 	if err != nil {
 		log.Fatalf("Failed to initialize tracer: %v", err)
 	}
 	defer shutdown()
 
-	// Configura os clientes
-	serviceBClient := repository.NewServiceBClient(config.GetString("service_b_url"))
-	weatherHandler := handler.NewWeatherHandler(serviceBClient)
+	// Certificados são recarregados do disco até o serviço parar
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+
+	serviceA := app.New(watchCtx, cfg)
+	defer serviceA.Close()
 
 	// Configura o servidor
 	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", config.GetInt("port")),
-		Handler:      weatherHandler.SetupRoutes(),
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		Addr:         fmt.Sprintf(":%d", cfg.Port),
+		Handler:      serviceA.Handler,
+		TLSConfig:    serviceA.TLSConfig,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
 	}
 
 	// Inicia o servidor
 	go func() {
-		log.Printf("Service A running on port %d", config.GetInt("port"))
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("Service A %s (%s) running on port %d (tls: %t)", version, commit, cfg.Port, server.TLSConfig != nil)
+		if err := listen(server); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Error starting server: %v", err)
 		}
 	}()
@@ -53,7 +85,7 @@ func main() {
 
 	log.Println("Stopping Service A...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
@@ -63,22 +95,26 @@ func main() {
 	log.Println("Service A stopped successfully")
 }
 
-func setupConfig() *viper.Viper {
-	v := viper.New()
-
-	v.SetDefault("port", 8080)
-	v.SetDefault("service_b_url", "http://localhost:8081")
-	v.SetDefault("zipkin_url", "http://localhost:9411/api/v2/spans")
+func listen(server *http.Server) error {
+	if server.TLSConfig != nil {
+		return server.ListenAndServeTLS("", "")
+	}
+	return server.ListenAndServe()
+}
 
-	v.AutomaticEnv()
+// setupConfig lê o .env (opcional) e as variáveis de ambiente e valida o
+// resultado; um .env inválido é erro, não apenas aviso
+func setupConfig() (*app.Config, error) {
+	v := app.NewConfig()
 
 	v.SetConfigFile(".env")
-	if err := v.ReadInConfig(); err != nil {
-		log.Printf("Warning: .env file not found: %v", err)
-		log.Println("Using default values and environment variables")
+	if err := v.ReadInConfig(); errors.Is(err, fs.ErrNotExist) {
+		log.Println(".env file not found, using default values and environment variables")
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading .env: %w", err)
 	} else {
 		log.Println(".env file loaded")
 	}
 
-	return v
+	return app.LoadConfig(v)
 }
@@ -0,0 +1,87 @@
+// Loadtest dispara POST /weather contra o Service A e imprime os percentis de
+// latência e os resultados por status e mensagem. Com -trace, cada requisição
+// gera um trace (loadtest.request) exportado como nos serviços (OTEL_*).
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/loadtest"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel"
+)
+
+// headers acumula as ocorrências de -header "Nome: valor"
+type headers []string
+
+func (h *headers) String() string { return strings.Join(*h, ", ") }
+
+func (h *headers) Set(value string) error {
+	if _, _, ok := strings.Cut(value, ":"); !ok {
+		return fmt.Errorf("expected \"Name: value\", got %q", value)
+	}
+	*h = append(*h, value)
+	return nil
+}
+
+func main() {
+	var extraHeaders headers
+	target := flag.String("target", "http://localhost:8080", "URL base do Service A")
+	rate := flag.Float64("rps", 0, "requisições por segundo (0 = sem limite além da concorrência)")
+	concurrency := flag.Int("concurrency", 10, "requisições simultâneas")
+	duration := flag.Duration("duration", 10*time.Second, "duração da carga")
+	requests := flag.Int("requests", 0, "encerra após N requisições (0 = até a duração)")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout de cada requisição")
+	corpus := flag.String("corpus", loadtest.CorpusValid, "corpus de CEPs: valid, invalid, mixed ou um arquivo (um CEP por linha)")
+	apiKey := flag.String("api-key", "", "API key enviada em X-API-Key")
+	trace := flag.Bool("trace", false, "exporta um trace por requisição")
+	zipkinURL := flag.String("zipkin-url", "", "coletor Zipkin dos traces (padrão OTEL_EXPORTER_ZIPKIN_ENDPOINT ou localhost:9411)")
+	flag.Var(&extraHeaders, "header", "header adicional \"Nome: valor\" (pode repetir)")
+	flag.Parse()
+
+	zipcodes, err := loadtest.LoadCorpus(*corpus)
+	if err != nil {
+		log.Fatalf("Invalid corpus: %v", err)
+	}
+
+	if *trace {
+		shutdown, err := otel.InitTracer("loadtest", otel.WithZipkinEndpoint(*zipkinURL))
+		if err != nil {
+			log.Fatalf("Failed to initialize tracer: %v", err)
+		}
+		defer shutdown()
+	}
+
+	opts := []loadtest.Option{
+		loadtest.WithRate(*rate),
+		loadtest.WithConcurrency(*concurrency),
+		loadtest.WithDuration(*duration),
+		loadtest.WithRequests(*requests),
+		loadtest.WithTimeout(*timeout),
+		loadtest.WithZipcodes(zipcodes),
+	}
+	if *apiKey != "" {
+		opts = append(opts, loadtest.WithHeader("X-API-Key", *apiKey))
+	}
+	for _, header := range extraHeaders {
+		name, value, _ := strings.Cut(header, ":")
+		opts = append(opts, loadtest.WithHeader(strings.TrimSpace(name), strings.TrimSpace(value)))
+	}
+
+	// Ctrl+C encerra a carga e imprime o relatório parcial
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Load testing %s/weather for %s (rps: %g, concurrency: %d, %d zipcodes)", *target, *duration, *rate, *concurrency, len(zipcodes))
+	report := loadtest.New(*target, opts...).Run(ctx)
+	if err := report.Write(os.Stdout); err != nil {
+		log.Fatalf("Error writing report: %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+// Weather consulta a temperatura de um ou mais CEPs no Service A, lidos dos
+// argumentos, de um arquivo ou da entrada padrão, e imprime o resultado em
+// tabela, JSON ou CSV. O contexto de trace de TRACEPARENT é propagado e, com
+// -trace, a consulta é exportada como os serviços (OTEL_*).
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/client"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const usage = `Usage:
+  weather [flags] [cep ...]   consulta a temperatura atual dos CEPs
+  weather forecast ...        previsão (não suportada pelo Service A)
+
+Sem CEPs nos argumentos nem -file, lê os CEPs da entrada padrão (um ou mais
+por linha, separados por espaço ou vírgula; linhas iniciadas por # são ignoradas).
+
+Flags:
+`
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("weather: ")
+
+	flags := flag.NewFlagSet("weather", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	baseURL := flags.String("url", envOr("SERVICE_A_URL", client.DefaultBaseURL), "URL base do Service A (SERVICE_A_URL)")
+	apiKey := flags.String("api-key", os.Getenv("SERVICE_A_API_KEY"), "API key enviada em X-API-Key (SERVICE_A_API_KEY)")
+	file := flags.String("file", "", "arquivo com os CEPs (- = entrada padrão)")
+	output := flags.String("output", "table", "formato da saída: table, json ou csv")
+	timeout := flags.Duration("timeout", 0, "timeout de cada tentativa (padrão do cliente: 10s)")
+	retries := flags.Int("retries", 2, "novas tentativas em falhas de conexão, 429, 502, 503 e 504")
+	units := flags.String("units", "", "escalas separadas por vírgula, na ordem da saída: C, F, K e R (padrão do Service A: C,F,K)")
+	precision := flags.Int("precision", -1, "casas decimais, de 0 a 6 (padrão do Service A: 1)")
+	scientificKelvin := flags.Bool("scientific-kelvin", false, "converte Kelvin com C + 273.15 em vez de C + 273")
+	traced := flags.Bool("trace", false, "exporta a consulta como trace (Zipkin ou OTEL_*)")
+	zipkinURL := flags.String("zipkin-url", "", "coletor Zipkin dos traces (padrão OTEL_EXPORTER_ZIPKIN_ENDPOINT ou localhost:9411)")
+
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "forecast" {
+		log.Fatal("forecast is not supported: service A only exposes the current temperature (POST /weather)")
+	}
+	flags.Parse(args)
+
+	write, ok := writers[*output]
+	if !ok {
+		log.Fatalf("invalid output %q: expected table, json or csv", *output)
+	}
+
+	zipcodes, err := readZipcodes(flags.Args(), *file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, shutdown := setupTracing(*traced, *zipkinURL)
+	defer shutdown()
+
+	opts := []client.Option{
+		client.WithBaseURL(*baseURL),
+		client.WithAPIKey(*apiKey),
+		client.WithRetries(*retries, 0),
+		client.WithUserAgent("weather-cli"),
+	}
+	if *timeout > 0 {
+		opts = append(opts, client.WithTimeout(*timeout))
+	}
+	req := client.WeatherRequest{ScientificKelvin: *scientificKelvin}
+	if *units != "" {
+		req.Units = strings.Split(strings.ToUpper(strings.ReplaceAll(*units, " ", "")), ",")
+	}
+	if *precision >= 0 {
+		req.Precision = precision
+	}
+	results := lookup(ctx, client.New(opts...), req, zipcodes)
+
+	if err := write(os.Stdout, results); err != nil {
+		log.Fatalf("error writing output: %v", err)
+	}
+	for _, result := range results {
+		if result.Error != "" {
+			shutdown()
+			os.Exit(1)
+		}
+	}
+}
+
+// setupTracing continua o trace de TRACEPARENT (e TRACESTATE), como em
+// ferramentas que propagam contexto por variáveis de ambiente; com traced, os
+// spans também são exportados. Sem nenhum dos dois, nada é propagado.
+func setupTracing(traced bool, zipkinURL string) (context.Context, func()) {
+	shutdown := func() {}
+	if traced {
+		var err error
+		shutdown, err = otel.InitTracer("weather-cli", otel.WithZipkinEndpoint(zipkinURL))
+		if err != nil {
+			log.Fatalf("failed to initialize tracer: %v", err)
+		}
+	} else if os.Getenv("TRACEPARENT") != "" {
+		otelapi.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	}
+
+	ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{
+		"traceparent": os.Getenv("TRACEPARENT"),
+		"tracestate":  os.Getenv("TRACESTATE"),
+	})
+	return ctx, shutdown
+}
+
+// result é uma linha da saída: a temperatura ou, se a consulta falhou, o erro
+type result struct {
+	CEP string `json:"cep"`
+	*client.WeatherResponse
+	Error string `json:"error,omitempty"`
+}
+
+// lookup consulta os CEPs em ordem com as opções de req, dentro do span weather-cli.lookup
+func lookup(ctx context.Context, c *client.Client, req client.WeatherRequest, zipcodes []string) []result {
+	ctx, span := otelapi.Tracer("weather-cli").Start(ctx, "weather-cli.lookup",
+		trace.WithAttributes(attribute.Int("weather.zipcodes", len(zipcodes))))
+	defer span.End()
+	if span.SpanContext().IsValid() {
+		log.Printf("trace_id %s", span.SpanContext().TraceID())
+	}
+
+	results := make([]result, 0, len(zipcodes))
+	for _, zipcode := range zipcodes {
+		req.CEP = zipcode
+		weather, err := c.GetWeatherWithOptions(ctx, req)
+		if err != nil {
+			span.SetStatus(codes.Error, "lookup failed")
+			results = append(results, result{CEP: zipcode, Error: err.Error()})
+			continue
+		}
+		results = append(results, result{CEP: zipcode, WeatherResponse: weather})
+	}
+	return results
+}
+
+// readZipcodes junta os CEPs dos argumentos e do arquivo; sem nenhum dos dois, lê
+// a entrada padrão. Hífens são removidos (26140-040 = 26140040).
+func readZipcodes(args []string, file string) ([]string, error) {
+	zipcodes := normalize(args)
+
+	var input io.Reader
+	switch {
+	case file == "-" || (file == "" && len(args) == 0):
+		input = os.Stdin
+	case file != "":
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("error opening %s: %w", file, err)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	if input != nil {
+		scanner := bufio.NewScanner(input)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if strings.HasPrefix(line, "#") {
+				continue
+			}
+			zipcodes = append(zipcodes, normalize(strings.FieldsFunc(line, func(r rune) bool {
+				return r == ',' || r == ' ' || r == '\t'
+			}))...)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("error reading zipcodes: %w", err)
+		}
+	}
+
+	if len(zipcodes) == 0 {
+		return nil, fmt.Errorf("no zipcodes given")
+	}
+	return zipcodes, nil
+}
+
+func normalize(values []string) []string {
+	zipcodes := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.ReplaceAll(strings.TrimSpace(value), "-", ""); value != "" {
+			zipcodes = append(zipcodes, value)
+		}
+	}
+	return zipcodes
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/client"
+)
+
+// writers imprime os resultados no formato escolhido em -output
+var writers = map[string]func(io.Writer, []result) error{
+	"table": writeTable,
+	"json":  writeJSON,
+	"csv":   writeCSV,
+}
+
+// scale é uma coluna de temperatura da saída
+type scale struct {
+	name string
+	temp func(*client.WeatherResponse) *float64
+}
+
+var allScales = []scale{
+	{name: "C", temp: func(w *client.WeatherResponse) *float64 { return w.TempC }},
+	{name: "F", temp: func(w *client.WeatherResponse) *float64 { return w.TempF }},
+	{name: "K", temp: func(w *client.WeatherResponse) *float64 { return w.TempK }},
+	{name: "R", temp: func(w *client.WeatherResponse) *float64 { return w.TempR }},
+}
+
+// scales são as colunas das escalas presentes em alguma resposta; sem nenhuma
+// (só erros), as escalas padrão do Service A (C, F e K)
+func scales(results []result) []scale {
+	var present []scale
+	for _, s := range allScales {
+		for _, r := range results {
+			if r.WeatherResponse != nil && s.temp(r.WeatherResponse) != nil {
+				present = append(present, s)
+				break
+			}
+		}
+	}
+	if len(present) == 0 {
+		return allScales[:3]
+	}
+	return present
+}
+
+// temps formata as temperaturas de r nas colunas de columns; escalas ausentes
+// (ou a consulta com erro) viram empty
+func temps(r result, columns []scale, format func(float64) string, empty string) []string {
+	values := make([]string, 0, len(columns))
+	for _, s := range columns {
+		var temp *float64
+		if r.WeatherResponse != nil {
+			temp = s.temp(r.WeatherResponse)
+		}
+		if temp == nil {
+			values = append(values, empty)
+			continue
+		}
+		values = append(values, format(*temp))
+	}
+	return values
+}
+
+func writeTable(w io.Writer, results []result) error {
+	columns := scales(results)
+	header := []string{"CEP", "CITY"}
+	for _, s := range columns {
+		header = append(header, "TEMP_"+s.name)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(append(header, "ERROR"), "\t"))
+	for _, r := range results {
+		city := "-"
+		if r.Error == "" {
+			city = r.City
+		}
+		row := append([]string{r.CEP, city}, temps(r, columns, formatTemp, "-")...)
+		fmt.Fprintln(tw, strings.Join(append(row, r.Error), "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, results []result) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+func writeCSV(w io.Writer, results []result) error {
+	columns := scales(results)
+	header := []string{"cep", "city"}
+	for _, s := range columns {
+		header = append(header, "temp_"+s.name)
+	}
+	cw := csv.NewWriter(w)
+	cw.Write(append(header, "error"))
+	for _, r := range results {
+		city := ""
+		if r.Error == "" {
+			city = r.City
+		}
+		row := append([]string{r.CEP, city}, temps(r, columns, formatTemp, "")...)
+		cw.Write(append(row, r.Error))
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatTemp(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
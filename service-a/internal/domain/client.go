@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+)
+
+// Client identifica um consumidor autenticado do gateway
+type Client struct {
+	ID   string
+	Name string
+	// Quota é o número de requisições permitidas por período; zero significa ilimitado
+	Quota int64
+	// Scopes concedidos ao cliente, usados na autorização por rota
+	Scopes []string
+}
+
+// HasScopes indica se o cliente possui todos os scopes informados
+func (c *Client) HasScopes(required ...string) bool {
+	for _, scope := range required {
+		if !slices.Contains(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
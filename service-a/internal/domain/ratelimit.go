@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimit define um token bucket: Requests fichas repostas a cada Period,
+// com capacidade Burst (igual a Requests quando não informado)
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// Enabled indica se o limite foi configurado
+func (l RateLimit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// Capacity retorna o tamanho do bucket
+func (l RateLimit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// TokensPerSecond retorna a taxa de reposição do bucket
+func (l RateLimit) TokensPerSecond() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// ParseRateLimit interpreta limites no formato "<requests>/<period>[:<burst>]",
+// como "10/s", "600/m", "100/30s:200". Uma string vazia desabilita o limite.
+func ParseRateLimit(value string) (RateLimit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return RateLimit{}, nil
+	}
+
+	spec, burstStr, hasBurst := strings.Cut(value, ":")
+	requestsStr, periodStr, ok := strings.Cut(spec, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: expected <requests>/<period>", value)
+	}
+
+	requests, err := strconv.Atoi(strings.TrimSpace(requestsStr))
+	if err != nil || requests <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: requests must be a positive integer", value)
+	}
+
+	periodStr = strings.TrimSpace(periodStr)
+	if periodStr != "" && (periodStr[0] < '0' || periodStr[0] > '9') {
+		periodStr = "1" + periodStr
+	}
+	period, err := time.ParseDuration(periodStr)
+	if err != nil || period <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: invalid period", value)
+	}
+
+	limit := RateLimit{Requests: requests, Period: period}
+	if hasBurst {
+		if limit.Burst, err = strconv.Atoi(strings.TrimSpace(burstStr)); err != nil || limit.Burst <= 0 {
+			return RateLimit{}, fmt.Errorf("invalid rate limit %q: burst must be a positive integer", value)
+		}
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  RateLimit
+		expectErr bool
+	}{
+		{"empty disables", "", RateLimit{}, false},
+		{"per second", "10/s", RateLimit{Requests: 10, Period: time.Second}, false},
+		{"per minute", "600/m", RateLimit{Requests: 600, Period: time.Minute}, false},
+		{"custom period with burst", "100/30s:200", RateLimit{Requests: 100, Period: 30 * time.Second, Burst: 200}, false},
+		{"missing period", "10", RateLimit{}, true},
+		{"invalid requests", "abc/s", RateLimit{}, true},
+		{"zero requests", "0/s", RateLimit{}, true},
+		{"invalid period", "10/fortnight", RateLimit{}, true},
+		{"invalid burst", "10/s:-1", RateLimit{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseRateLimit(tt.value)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestRateLimitCapacity(t *testing.T) {
+	assert.Equal(t, 10, RateLimit{Requests: 10, Period: time.Second}.Capacity())
+	assert.Equal(t, 25, RateLimit{Requests: 10, Period: time.Second, Burst: 25}.Capacity())
+	assert.Equal(t, 0.5, RateLimit{Requests: 30, Period: time.Minute}.TokensPerSecond())
+	assert.False(t, RateLimit{}.Enabled())
+}
@@ -1,16 +1,45 @@
 package dto
 
+import (
+	"encoding/xml"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/decode"
+)
+
+// WeatherRequest é repassada ao Service B, que aplica as opções de conversão
 type WeatherRequest struct {
 	CEP string `json:"cep" validate:"required,len=8"`
+	// Units são as escalas da resposta (C, F, K e R), na ordem desejada; vazio = C, F e K
+	Units []string `json:"units,omitempty" validate:"omitempty,max=4,unique,dive,oneof=C F K R"`
+	// Precision é o número de casas decimais; ausente = 1
+	Precision *int `json:"precision,omitempty" validate:"omitempty,min=0,max=6"`
+	// ScientificKelvin usa K = C + 273.15 em vez de C + 273
+	ScientificKelvin bool `json:"scientific_kelvin,omitempty"`
 }
 
+// WeatherResponse traz apenas as escalas pedidas; as demais ficam nil e fora da resposta
 type WeatherResponse struct {
-	City  string  `json:"city"`
-	TempC float64 `json:"temp_C"`
-	TempF float64 `json:"temp_F"`
-	TempK float64 `json:"temp_K"`
+	XMLName xml.Name `json:"-" xml:"weather"`
+	City    string   `json:"city" xml:"city"`
+	TempC   *float64 `json:"temp_C,omitempty" xml:"temp_C,omitempty"`
+	TempF   *float64 `json:"temp_F,omitempty" xml:"temp_F,omitempty"`
+	TempK   *float64 `json:"temp_K,omitempty" xml:"temp_K,omitempty"`
+	TempR   *float64 `json:"temp_R,omitempty" xml:"temp_R,omitempty"`
 }
 
 type ErrorResponse struct {
+	XMLName xml.Name `json:"-" xml:"error"`
+	Message string   `json:"message" xml:"message"`
+	// Errors detalha os campos inválidos do body, quando houver
+	Errors []decode.FieldError `json:"errors,omitempty" xml:"errors>error,omitempty"`
+}
+
+// ProblemResponse segue a RFC 7807 (application/problem+json). O campo message
+// repete o detail para clientes que já leem ErrorResponse.
+type ProblemResponse struct {
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	Status  int    `json:"status"`
+	Detail  string `json:"detail"`
 	Message string `json:"message"`
 }
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/repository"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderAPIKey é o header alternativo ao "Authorization: Bearer <key>"
+const HeaderAPIKey = "X-API-Key"
+
+type clientContextKey struct{}
+
+// ClientFromContext retorna o cliente autenticado pela requisição
+func ClientFromContext(ctx context.Context) (*domain.Client, bool) {
+	client, ok := ctx.Value(clientContextKey{}).(*domain.Client)
+	return client, ok
+}
+
+// Authenticator valida API keys ou JWTs e contabiliza a quota de cada cliente
+type Authenticator struct {
+	keys   repository.APIKeyStore
+	tokens TokenVerifier
+	quotas repository.QuotaStore
+}
+
+// AuthenticatorOption customiza o Authenticator
+type AuthenticatorOption func(*Authenticator)
+
+// WithTokenVerifier aceita JWTs em "Authorization: Bearer" além das API keys
+func WithTokenVerifier(tokens TokenVerifier) AuthenticatorOption {
+	return func(a *Authenticator) {
+		a.tokens = tokens
+	}
+}
+
+// NewAuthenticator cria o Authenticator; keys pode ser nil quando apenas JWTs são aceitos
+func NewAuthenticator(keys repository.APIKeyStore, quotas repository.QuotaStore, opts ...AuthenticatorOption) *Authenticator {
+	a := &Authenticator{
+		keys:   keys,
+		quotas: quotas,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Middleware rejeita requisições sem credencial válida (401) ou com quota esgotada (429)
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		client, err := a.authenticate(ctx, r)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, domain.ErrUnauthorized.Error())
+			w.Header().Set("WWW-Authenticate", `Bearer realm="service-a"`)
+			writeErrorResponse(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		span.SetAttributes(semconv.EnduserID(client.ID))
+		if len(client.Scopes) > 0 {
+			span.SetAttributes(semconv.EnduserScope(strings.Join(client.Scopes, " ")))
+		}
+
+		if client.Quota > 0 {
+			usage, err := a.quotas.Consume(ctx, client.ID, client.Quota)
+			w.Header().Set("X-Quota-Limit", strconv.FormatInt(usage.Limit, 10))
+			w.Header().Set("X-Quota-Remaining", strconv.FormatInt(usage.Remaining, 10))
+			w.Header().Set("X-Quota-Reset", strconv.FormatInt(usage.ResetAt.Unix(), 10))
+			span.SetAttributes(attribute.Int64("quota.remaining", usage.Remaining))
+
+			if errors.Is(err, domain.ErrQuotaExceeded) {
+				span.SetStatus(codes.Error, err.Error())
+				writeErrorResponse(w, http.StatusTooManyRequests, "quota exceeded")
+				return
+			}
+			if err != nil {
+				log.Printf("Error consuming quota: %v", err)
+				writeErrorResponse(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, clientContextKey{}, client)))
+	})
+}
+
+// authenticate valida o JWT quando há um verificador configurado e o bearer tem
+// formato de JWT; caso contrário trata a credencial como API key
+func (a *Authenticator) authenticate(ctx context.Context, r *http.Request) (*domain.Client, error) {
+	key := apiKeyFromRequest(r)
+	if a.tokens != nil && isJWT(key) {
+		return a.tokens.Verify(ctx, key)
+	}
+
+	if a.keys == nil {
+		return nil, domain.ErrUnauthorized
+	}
+	return a.keys.FindByKey(ctx, key)
+}
+
+// apiKeyFromRequest lê a chave do header X-API-Key ou de "Authorization: Bearer"
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get(HeaderAPIKey); key != "" {
+		return key
+	}
+
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
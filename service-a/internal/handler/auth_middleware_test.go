@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	telemetry "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel/oteltest"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+func TestAuthenticatorMiddleware(t *testing.T) {
+	keys := repository.NewInMemoryAPIKeyStore(map[string]domain.Client{
+		"key-ops":     {ID: "ops", Name: "Ops"},
+		"key-limited": {ID: "limited", Name: "Limited", Quota: 1},
+	})
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		requests       int
+		expectedStatus int
+		expectedBody   string
+		expectedClient string
+		expectedQuota  string
+		expectServiceB bool
+	}{
+		{
+			name:           "missing api key",
+			requests:       1,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"message":"unauthorized"}`,
+		},
+		{
+			name:           "invalid api key",
+			headers:        map[string]string{HeaderAPIKey: "wrong"},
+			requests:       1,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"message":"unauthorized"}`,
+		},
+		{
+			name:           "api key header",
+			headers:        map[string]string{HeaderAPIKey: "key-ops"},
+			requests:       1,
+			expectedStatus: http.StatusOK,
+			expectedClient: "ops",
+			expectServiceB: true,
+		},
+		{
+			name:           "bearer token overrides spoofed client id",
+			headers:        map[string]string{"Authorization": "Bearer key-ops", HeaderClientID: "someone-else"},
+			requests:       1,
+			expectedStatus: http.StatusOK,
+			expectedClient: "ops",
+			expectServiceB: true,
+		},
+		{
+			name:           "quota exceeded",
+			headers:        map[string]string{HeaderAPIKey: "key-limited"},
+			requests:       2,
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   `{"message":"quota exceeded"}`,
+			expectedClient: "limited",
+			expectedQuota:  "0",
+			expectServiceB: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := oteltest.Record(t)
+			propagator, _ := telemetry.NewPropagator(nil)
+			otel.SetTextMapPropagator(propagator)
+			defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+			// Service B mock registra o client ID recebido via baggage
+			var receivedClient string
+			serviceBCalled := false
+			serviceB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				serviceBCalled = true
+				ctx := propagator.Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+				receivedClient = baggage.FromContext(ctx).Member(telemetry.BaggageClientID).Value()
+				w.Write([]byte(`{"city":"Belford Roxo","temp_C":25.5,"temp_F":77.9,"temp_K":298.5}`))
+			}))
+			defer serviceB.Close()
+
+			authenticator := NewAuthenticator(keys, repository.NewInMemoryQuotaStore(time.Hour))
+			router := NewWeatherHandler(
+				repository.NewServiceBClient(serviceB.URL),
+				WithMiddleware(authenticator.Middleware),
+			).SetupRoutes()
+
+			var rec *httptest.ResponseRecorder
+			for i := 0; i < tt.requests; i++ {
+				req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(`{"cep":"26140040"}`))
+				for key, value := range tt.headers {
+					req.Header.Set(key, value)
+				}
+				rec = httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+			}
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			}
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+			}
+			if tt.expectedQuota != "" {
+				assert.Equal(t, tt.expectedQuota, rec.Header().Get("X-Quota-Remaining"))
+			}
+			assert.Equal(t, tt.expectServiceB, serviceBCalled)
+			if tt.expectServiceB {
+				assert.Equal(t, tt.expectedClient, receivedClient)
+			}
+
+			// A identidade do cliente fica registrada no span da requisição
+			for _, s := range recorder.Ended() {
+				if s.Name() == "service-a.handle-request" && tt.expectedClient != "" {
+					assert.Contains(t, s.Attributes(), semconv.EnduserID(tt.expectedClient))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/repository"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenVerifier valida bearer tokens e retorna o cliente autenticado
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*domain.Client, error)
+}
+
+// tokenClaims aceita scopes como string separada por espaços ("scope", RFC 8693)
+// ou como lista ("scp", usado por alguns provedores)
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Scope string   `json:"scope"`
+	Scp   []string `json:"scp"`
+}
+
+func (c tokenClaims) scopes() []string {
+	if len(c.Scp) > 0 {
+		return c.Scp
+	}
+	return strings.Fields(c.Scope)
+}
+
+// JWTVerifier valida assinatura (via JWKS), emissor, audiência e expiração de JWTs
+type JWTVerifier struct {
+	keys   repository.JWKSClient
+	parser *jwt.Parser
+}
+
+func NewJWTVerifier(keys repository.JWKSClient, issuer, audience string) *JWTVerifier {
+	return &JWTVerifier{
+		keys: keys,
+		parser: jwt.NewParser(
+			jwt.WithIssuer(issuer),
+			jwt.WithAudience(audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(30*time.Second),
+			// Apenas algoritmos assimétricos: HS* permitiria forjar tokens com a chave pública
+			jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		),
+	}
+}
+
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*domain.Client, error) {
+	var claims tokenClaims
+	_, err := v.parser.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrUnauthorized, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", domain.ErrUnauthorized)
+	}
+
+	return &domain.Client{
+		ID:     claims.Subject,
+		Name:   claims.Subject,
+		Scopes: claims.scopes(),
+	}, nil
+}
+
+// isJWT distingue tokens JWT (três segmentos) de API keys enviadas como bearer
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel/oteltest"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/repository"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// newJWKSServer publica a chave pública como um provedor de identidade local
+func newJWKSServer(t *testing.T, kid string, key *rsa.PublicKey) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": kid,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return signed
+}
+
+func TestJWTAuthentication(t *testing.T) {
+	const (
+		issuer   = "https://idp.example.com"
+		audience = "service-a"
+	)
+	signingKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	jwks := newJWKSServer(t, "key-1", &signingKey.PublicKey)
+	defer jwks.Close()
+
+	claims := func(overrides jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":   issuer,
+			"aud":   audience,
+			"sub":   "user-123",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"scope": "weather:read profile",
+		}
+		for k, v := range overrides {
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+		expectedAuth   string
+		expectedScopes string
+		expectServiceB bool
+	}{
+		{
+			name:           "valid token",
+			token:          signToken(t, jwt.SigningMethodRS256, "key-1", signingKey, claims(nil)),
+			expectedStatus: http.StatusOK,
+			expectedScopes: "weather:read profile",
+			expectServiceB: true,
+		},
+		{
+			name:           "scopes as list",
+			token:          signToken(t, jwt.SigningMethodRS256, "key-1", signingKey, claims(jwt.MapClaims{"scope": nil, "scp": []string{"weather:read"}})),
+			expectedStatus: http.StatusOK,
+			expectedScopes: "weather:read",
+			expectServiceB: true,
+		},
+		{
+			name:           "expired token",
+			token:          signToken(t, jwt.SigningMethodRS256, "key-1", signingKey, claims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing expiration",
+			token:          signToken(t, jwt.SigningMethodRS256, "key-1", signingKey, claims(jwt.MapClaims{"exp": nil})),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "wrong issuer",
+			token:          signToken(t, jwt.SigningMethodRS256, "key-1", signingKey, claims(jwt.MapClaims{"iss": "https://evil.example.com"})),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "wrong audience",
+			token:          signToken(t, jwt.SigningMethodRS256, "key-1", signingKey, claims(jwt.MapClaims{"aud": "service-b"})),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "unknown kid",
+			token:          signToken(t, jwt.SigningMethodRS256, "key-2", signingKey, claims(nil)),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "signed with another key",
+			token:          signToken(t, jwt.SigningMethodRS256, "key-1", otherKey, claims(nil)),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "symmetric algorithm rejected",
+			token:          signToken(t, jwt.SigningMethodHS256, "key-1", []byte("secret"), claims(nil)),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing subject",
+			token:          signToken(t, jwt.SigningMethodRS256, "key-1", signingKey, claims(jwt.MapClaims{"sub": nil})),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "insufficient scope",
+			token:          signToken(t, jwt.SigningMethodRS256, "key-1", signingKey, claims(jwt.MapClaims{"scope": "profile"})),
+			expectedStatus: http.StatusForbidden,
+			expectedAuth:   `Bearer realm="service-a", error="insufficient_scope", scope="weather:read"`,
+			expectedScopes: "profile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := oteltest.Record(t)
+
+			serviceBCalled := false
+			serviceB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				serviceBCalled = true
+				w.Write([]byte(`{"city":"Belford Roxo","temp_C":25.5,"temp_F":77.9,"temp_K":298.5}`))
+			}))
+			defer serviceB.Close()
+
+			verifier := NewJWTVerifier(repository.NewJWKSClient(jwks.URL, time.Hour, time.Minute), issuer, audience)
+			authenticator := NewAuthenticator(nil, repository.NewInMemoryQuotaStore(time.Hour), WithTokenVerifier(verifier))
+			authorizer := NewScopeAuthorizer(map[string][]string{
+				"/weather": {"weather:read"},
+				"/admin/*": {"admin"},
+			})
+			router := NewWeatherHandler(
+				repository.NewServiceBClient(serviceB.URL),
+				WithMiddleware(authenticator.Middleware, authorizer.Middleware),
+			).SetupRoutes()
+
+			req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(`{"cep":"26140040"}`))
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectServiceB, serviceBCalled)
+			if tt.expectedAuth != "" {
+				assert.Equal(t, tt.expectedAuth, rec.Header().Get("WWW-Authenticate"))
+			}
+
+			// O subject e os scopes do token ficam registrados no span da requisição
+			if tt.expectedScopes != "" {
+				var found bool
+				for _, s := range recorder.Ended() {
+					if s.Name() == "service-a.handle-request" {
+						found = true
+						assert.Contains(t, s.Attributes(), semconv.EnduserID("user-123"))
+						assert.Contains(t, s.Attributes(), semconv.EnduserScope(tt.expectedScopes))
+					}
+				}
+				assert.True(t, found)
+			}
+		})
+	}
+}
+
+func TestScopeAuthorizerRequiredScopes(t *testing.T) {
+	authorizer := NewScopeAuthorizer(map[string][]string{
+		"/weather": {"weather:read"},
+		"/admin/*": {"admin"},
+	})
+
+	tests := []struct {
+		route    string
+		expected []string
+	}{
+		{route: "/weather", expected: []string{"weather:read"}},
+		{route: "/admin/keys", expected: []string{"admin"}},
+		{route: "/admin/keys/{id}", expected: []string{"admin"}},
+		{route: "/administrator", expected: nil},
+		{route: "/healthz", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			assert.Equal(t, tt.expected, authorizer.requiredScopes(tt.route))
+		})
+	}
+}
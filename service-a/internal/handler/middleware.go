@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	telemetry "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Headers usados para preencher o baggage propagado ao Service B
+const (
+	HeaderClientID      = "X-Client-ID"
+	HeaderRequestSource = "X-Request-Source"
+)
+
+const defaultRequestSource = "http"
+
+// withBaggage substitui o baggage recebido pelo client ID e pela origem da
+// requisição, que também vão para o span atual. O Service A é a borda: o
+// baggage do chamador é descartado para que não forje o client.id repassado ao
+// Service B. Com autenticação habilitada o client ID vem da credencial, não do
+// header X-Client-ID (que sem autenticação é apenas declarado pelo chamador).
+func withBaggage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(baggage.ContextWithoutBaggage(r.Context()))
+
+		source := r.Header.Get(HeaderRequestSource)
+		if source == "" {
+			source = defaultRequestSource
+		}
+
+		clientID := r.Header.Get(HeaderClientID)
+		if client, ok := ClientFromContext(r.Context()); ok {
+			clientID = client.ID
+		}
+
+		ctx, err := telemetry.ContextWithBaggage(r.Context(), map[string]string{
+			telemetry.BaggageClientID:      clientID,
+			telemetry.BaggageRequestSource: source,
+		})
+		if err != nil {
+			log.Printf("Ignoring invalid baggage: %v", err)
+		}
+
+		trace.SpanFromContext(ctx).SetAttributes(telemetry.BaggageAttributes(ctx)...)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// realIP troca o RemoteAddr pelo IP do cliente informado em X-Forwarded-For ou
+// X-Real-IP, mas só quando a conexão vem de um proxy confiável
+// (TRUSTED_PROXIES). Sem proxies confiáveis os headers são ignorados: qualquer
+// cliente poderia trocá-los a cada requisição para ganhar um bucket novo no
+// rate limiting por IP.
+func realIP(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip, ok := forwardedIP(r, trusted); ok {
+				r.RemoteAddr = ip
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedIP lê X-Forwarded-For da direita para a esquerda, pulando os proxies
+// confiáveis: o primeiro endereço fora deles é o do cliente, já que os da
+// esquerda podem ter sido enviados pelo próprio cliente
+func forwardedIP(r *http.Request, trusted []netip.Prefix) (string, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	peer, err := netip.ParseAddr(host)
+	if err != nil || !isTrusted(peer, trusted) {
+		return "", false
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		value := strings.TrimSpace(forwarded[i])
+		if value == "" {
+			continue
+		}
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return "", false
+		}
+		if !isTrusted(addr, trusted) {
+			return addr.String(), true
+		}
+	}
+
+	if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return addr.String(), true
+	}
+	return "", false
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Printf("Error writing JSON response: %v", err)
+	}
+}
+
+func writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
+	writeJSONResponse(w, statusCode, dto.ErrorResponse{Message: message})
+}
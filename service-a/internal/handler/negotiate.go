@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	weatherv1 "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/api/weather/v1"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Media types canônicos; com curingas (ex: */*) a resposta usa o primeiro
+// media type de cada encoder que o range cobrir
+const (
+	MediaTypeJSON     = "application/json"
+	MediaTypeXML      = "application/xml"
+	MediaTypeCSV      = "text/csv"
+	MediaTypeProtobuf = "application/x-protobuf"
+)
+
+var errUnsupportedPayload = errors.New("payload not supported by format")
+
+// encoder serializa os payloads do handler em um formato de resposta
+type encoder struct {
+	mediaTypes []string
+	encode     func(w io.Writer, data interface{}) error
+}
+
+// encoders em ordem de preferência do servidor: Accept vazio ou com curingas
+// de mesma qualidade resolvem para JSON, mantendo o contrato original
+var encoders = []encoder{
+	{mediaTypes: []string{MediaTypeJSON}, encode: encodeJSON},
+	{mediaTypes: []string{MediaTypeXML, "text/xml"}, encode: encodeXML},
+	{mediaTypes: []string{MediaTypeCSV}, encode: encodeCSV},
+	{mediaTypes: []string{MediaTypeProtobuf, "application/protobuf", "application/vnd.google.protobuf"}, encode: encodeProtobuf},
+}
+
+// mediaRange é um item do header Accept (ex: text/*;q=0.5)
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, item := range strings.Split(accept, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		mediaType, params, err := mime.ParseMediaType(item)
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+	}
+	return ranges
+}
+
+// qualityOf retorna a qualidade do range mais específico que cobre mediaType
+// e a especificidade dele (2 exato, 1 tipo/*, 0 */*), ou -1 se nenhum cobrir
+func qualityOf(ranges []mediaRange, mediaType string) (float64, int) {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	quality, specificity := -1.0, -1
+	for _, r := range ranges {
+		s := -1
+		switch r.mediaType {
+		case mediaType:
+			s = 2
+		case typ + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		}
+		if s > specificity {
+			quality, specificity = r.quality, s
+		}
+	}
+	return quality, specificity
+}
+
+// negotiate escolhe o encoder e o Content-Type da resposta a partir do Accept:
+// vence a maior qualidade; em empate, o formato pedido explicitamente e depois
+// a ordem de encoders. Retorna false se nenhum formato for aceitável (406).
+func negotiate(accept string) (*encoder, string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return &encoders[0], MediaTypeJSON, true
+	}
+
+	ranges := parseAccept(accept)
+	var (
+		best            *encoder
+		bestType        string
+		bestQuality     float64
+		bestSpecificity = -1
+	)
+	for i := range encoders {
+		for _, mediaType := range encoders[i].mediaTypes {
+			q, specificity := qualityOf(ranges, mediaType)
+			if q <= 0 || q < bestQuality || (q == bestQuality && specificity <= bestSpecificity) {
+				continue
+			}
+			best, bestType, bestQuality, bestSpecificity = &encoders[i], mediaType, q, specificity
+		}
+	}
+	if best == nil {
+		return nil, "", false
+	}
+	return best, bestType, true
+}
+
+// writeResponse serializa data no formato negociado pelo Accept da requisição.
+// Falhas de serialização viram 500 em JSON, já que nada foi escrito ainda.
+func writeResponse(w http.ResponseWriter, r *http.Request, statusCode int, data interface{}) {
+	enc, contentType, ok := negotiate(r.Header.Get("Accept"))
+	if !ok {
+		enc, contentType = &encoders[0], MediaTypeJSON
+	}
+
+	var buf bytes.Buffer
+	if err := enc.encode(&buf, data); err != nil {
+		log.Printf("Error encoding %s response: %v", contentType, err)
+		writeErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Printf("Error writing %s response: %v", contentType, err)
+	}
+}
+
+func encodeJSON(w io.Writer, data interface{}) error {
+	return json.NewEncoder(w).Encode(data)
+}
+
+func encodeXML(w io.Writer, data interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(data)
+}
+
+// encodeCSV escreve uma linha de cabeçalho seguida dos registros. Erros com
+// campos inválidos geram um registro por campo.
+func encodeCSV(w io.Writer, data interface{}) error {
+	var records [][]string
+	switch v := data.(type) {
+	case *dto.WeatherResponse:
+		// Apenas as escalas presentes viram colunas
+		header, record := []string{"city"}, []string{v.City}
+		for _, temp := range []struct {
+			column string
+			value  *float64
+		}{{"temp_C", v.TempC}, {"temp_F", v.TempF}, {"temp_K", v.TempK}, {"temp_R", v.TempR}} {
+			if temp.value != nil {
+				header = append(header, temp.column)
+				record = append(record, formatFloat(*temp.value))
+			}
+		}
+		records = [][]string{header, record}
+	case dto.ErrorResponse:
+		records = [][]string{{"message", "field", "field_message"}}
+		if len(v.Errors) == 0 {
+			records = append(records, []string{v.Message, "", ""})
+		}
+		for _, field := range v.Errors {
+			records = append(records, []string{v.Message, field.Field, field.Message})
+		}
+	default:
+		return fmt.Errorf("%w: %T", errUnsupportedPayload, data)
+	}
+
+	writer := csv.NewWriter(w)
+	return writer.WriteAll(records)
+}
+
+// encodeProtobuf usa as mensagens de pkg/api/weather/v1, as mesmas do transporte gRPC
+func encodeProtobuf(w io.Writer, data interface{}) error {
+	var msg proto.Message
+	switch v := data.(type) {
+	case *dto.WeatherResponse:
+		msg = &weatherv1.GetWeatherResponse{City: v.City, TempC: v.TempC, TempF: v.TempF, TempK: v.TempK, TempR: v.TempR}
+	case dto.ErrorResponse:
+		errResp := &weatherv1.ErrorResponse{Message: v.Message}
+		for _, field := range v.Errors {
+			errResp.Errors = append(errResp.Errors, &weatherv1.FieldError{Field: field.Field, Message: field.Message})
+		}
+		msg = errResp
+	default:
+		return fmt.Errorf("%w: %T", errUnsupportedPayload, data)
+	}
+
+	out, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	weatherv1 "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/api/weather/v1"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name                string
+		accept              string
+		expectedContentType string
+		expectedOK          bool
+	}{
+		{name: "empty accept defaults to json", accept: "", expectedContentType: MediaTypeJSON, expectedOK: true},
+		{name: "any type defaults to json", accept: "*/*", expectedContentType: MediaTypeJSON, expectedOK: true},
+		{name: "json", accept: "application/json", expectedContentType: MediaTypeJSON, expectedOK: true},
+		{name: "xml", accept: "application/xml", expectedContentType: MediaTypeXML, expectedOK: true},
+		{name: "text xml alias", accept: "text/xml", expectedContentType: "text/xml", expectedOK: true},
+		{name: "csv", accept: "text/csv", expectedContentType: MediaTypeCSV, expectedOK: true},
+		{name: "protobuf", accept: "application/x-protobuf", expectedContentType: MediaTypeProtobuf, expectedOK: true},
+		{name: "protobuf alias", accept: "application/protobuf", expectedContentType: "application/protobuf", expectedOK: true},
+		{name: "type wildcard stays within the range", accept: "text/*", expectedContentType: "text/xml", expectedOK: true},
+		{name: "application wildcard defaults to json", accept: "application/*", expectedContentType: MediaTypeJSON, expectedOK: true},
+		{name: "highest quality wins", accept: "application/json;q=0.5, text/csv;q=0.9", expectedContentType: MediaTypeCSV, expectedOK: true},
+		{name: "explicit type beats wildcard of same quality", accept: "*/*, application/xml", expectedContentType: MediaTypeXML, expectedOK: true},
+		{name: "browser accept prefers xml", accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", expectedContentType: MediaTypeXML, expectedOK: true},
+		{name: "q zero excludes format", accept: "application/json;q=0, */*", expectedContentType: MediaTypeXML, expectedOK: true},
+		{name: "unsupported type", accept: "image/png", expectedOK: false},
+		{name: "everything excluded", accept: "*/*;q=0", expectedOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, contentType, ok := negotiate(tt.accept)
+
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedContentType, contentType)
+		})
+	}
+}
+
+func TestWeatherHandlerContentNegotiation(t *testing.T) {
+	weather := &dto.WeatherResponse{City: "Belford Roxo", TempC: proto.Float64(25.5), TempF: proto.Float64(77.9), TempK: proto.Float64(298.5)}
+
+	tests := []struct {
+		name                string
+		accept              string
+		body                string
+		expectServiceB      bool
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+		expectedMessage     proto.Message
+	}{
+		{
+			name:                "weather as xml",
+			accept:              "application/xml",
+			body:                `{"cep":"26140040"}`,
+			expectServiceB:      true,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: MediaTypeXML,
+			expectedBody:        `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<weather><city>Belford Roxo</city><temp_C>25.5</temp_C><temp_F>77.9</temp_F><temp_K>298.5</temp_K></weather>`,
+		},
+		{
+			name:                "weather as csv",
+			accept:              "text/csv",
+			body:                `{"cep":"26140040"}`,
+			expectServiceB:      true,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: MediaTypeCSV,
+			expectedBody:        "city,temp_C,temp_F,temp_K\nBelford Roxo,25.5,77.9,298.5\n",
+		},
+		{
+			name:                "weather as protobuf",
+			accept:              "application/x-protobuf",
+			body:                `{"cep":"26140040"}`,
+			expectServiceB:      true,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: MediaTypeProtobuf,
+			expectedMessage:     &weatherv1.GetWeatherResponse{City: "Belford Roxo", TempC: proto.Float64(25.5), TempF: proto.Float64(77.9), TempK: proto.Float64(298.5)},
+		},
+		{
+			name:                "error as xml",
+			accept:              "application/xml",
+			body:                `{"cep":"123"}`,
+			expectedStatus:      http.StatusUnprocessableEntity,
+			expectedContentType: MediaTypeXML,
+			expectedBody:        `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<error><message>invalid zipcode</message><errors><error><field>cep</field><message>must have exactly 8 characters</message></error></errors></error>`,
+		},
+		{
+			name:                "error as csv",
+			accept:              "text/csv",
+			body:                `{"cep":"1234567a"}`,
+			expectedStatus:      http.StatusUnprocessableEntity,
+			expectedContentType: MediaTypeCSV,
+			expectedBody:        "message,field,field_message\ninvalid zipcode,,\n",
+		},
+		{
+			name:                "error as protobuf",
+			accept:              "application/x-protobuf",
+			body:                `{"cep":"123"}`,
+			expectedStatus:      http.StatusUnprocessableEntity,
+			expectedContentType: MediaTypeProtobuf,
+			expectedMessage: &weatherv1.ErrorResponse{
+				Message: "invalid zipcode",
+				Errors:  []*weatherv1.FieldError{{Field: "cep", Message: "must have exactly 8 characters"}},
+			},
+		},
+		{
+			name:                "not acceptable",
+			accept:              "image/png",
+			body:                `{"cep":"26140040"}`,
+			expectedStatus:      http.StatusNotAcceptable,
+			expectedContentType: MediaTypeJSON,
+			expectedBody:        `{"message":"not acceptable"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockServiceBClient)
+			if tt.expectServiceB {
+				mockClient.On("GetWeather", mock.Anything, dto.WeatherRequest{CEP: "26140040"}).Return(weather, nil)
+			}
+			router := NewWeatherHandler(mockClient).SetupRoutes()
+
+			req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedContentType, rec.Header().Get("Content-Type"))
+			if tt.expectedMessage != nil {
+				got := tt.expectedMessage.ProtoReflect().New().Interface()
+				require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), got))
+				assert.True(t, proto.Equal(tt.expectedMessage, got), "got %v", got)
+			} else {
+				assert.Equal(t, tt.expectedBody, rec.Body.String())
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
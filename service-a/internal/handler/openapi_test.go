@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/openapi"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/api"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestWeatherHandlerMatchesOpenAPISpec garante que as respostas do handler
+// seguem api/openapi.json; uma divergência vira 500 no modo de teste. O
+// validador é o de app.setupValidator: o body fica com o decoder.
+func TestWeatherHandlerMatchesOpenAPISpec(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		accept         string
+		body           string
+		mockResponse   *dto.WeatherResponse
+		mockErr        error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "success",
+			contentType:    "application/json",
+			body:           `{"cep":"26140040"}`,
+			mockResponse:   &dto.WeatherResponse{City: "Belford Roxo", TempC: proto.Float64(25.5), TempF: proto.Float64(77.9), TempK: proto.Float64(298.5)},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid zipcode",
+			contentType:    "application/json",
+			body:           `{"cep":"123"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "zipcode not found",
+			contentType:    "application/json",
+			body:           `{"cep":"99999999"}`,
+			mockErr:        domain.NewServiceError(http.StatusNotFound, "can not find zipcode"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "service b unavailable",
+			contentType:    "application/json",
+			body:           `{"cep":"26140040"}`,
+			mockErr:        errors.New("connection refused"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "success with unit subset",
+			contentType:    "application/json",
+			body:           `{"cep":"26140040","units":["R"],"precision":2}`,
+			mockResponse:   &dto.WeatherResponse{City: "Belford Roxo", TempR: proto.Float64(537.57)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"city":"Belford Roxo","temp_R":537.57}`,
+		},
+		{
+			name:           "unknown unit",
+			contentType:    "application/json",
+			body:           `{"cep":"26140040","units":["X"]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"message":"validation failed","errors":[{"field":"units[0]","message":"must be one of: C F K R"}]}`,
+		},
+		{
+			name:           "missing required cep",
+			contentType:    "application/json",
+			body:           `{}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"message":"invalid zipcode","errors":[{"field":"cep","message":"is required"}]}`,
+		},
+		{
+			name:           "cep is not a string",
+			contentType:    "application/json",
+			body:           `{"cep":26140040}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"message":"invalid request body","errors":[{"field":"cep","message":"must be a string"}]}`,
+		},
+		{
+			name:           "malformed json",
+			contentType:    "application/json",
+			body:           `{"cep":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"message":"invalid request body"}`,
+		},
+		{
+			name:           "success as xml",
+			contentType:    "application/json",
+			accept:         "application/xml",
+			body:           `{"cep":"26140040"}`,
+			mockResponse:   &dto.WeatherResponse{City: "Belford Roxo", TempC: proto.Float64(25.5), TempF: proto.Float64(77.9), TempK: proto.Float64(298.5)},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "success as csv",
+			contentType:    "application/json",
+			accept:         "text/csv",
+			body:           `{"cep":"26140040"}`,
+			mockResponse:   &dto.WeatherResponse{City: "Belford Roxo", TempC: proto.Float64(25.5), TempF: proto.Float64(77.9), TempK: proto.Float64(298.5)},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid zipcode as protobuf",
+			contentType:    "application/json",
+			accept:         "application/x-protobuf",
+			body:           `{"cep":"123"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "not acceptable",
+			contentType:    "application/json",
+			accept:         "image/png",
+			body:           `{"cep":"26140040"}`,
+			expectedStatus: http.StatusNotAcceptable,
+		},
+		{
+			name:           "unsupported content type",
+			contentType:    "text/plain",
+			body:           `{"cep":"26140040"}`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   `{"message":"unsupported media type"}`,
+		},
+	}
+
+	validator, err := openapi.NewValidator(api.OpenAPISpec)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockServiceBClient)
+			if tt.mockResponse != nil || tt.mockErr != nil {
+				mockClient.On("GetWeather", mock.Anything, mock.Anything).Return(tt.mockResponse, tt.mockErr)
+			}
+			router := NewWeatherHandler(mockClient, WithMiddleware(validator.Middleware)).SetupRoutes()
+
+			req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+			assert.NotContains(t, rec.Body.String(), "does not match OpenAPI spec")
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
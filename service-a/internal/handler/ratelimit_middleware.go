@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RateLimitConfig define os limites aplicados pelo RateLimiter. Limites não
+// configurados (zero) são ignorados.
+type RateLimitConfig struct {
+	// Global é compartilhado por todos os clientes e protege a quota dos provedores
+	Global domain.RateLimit
+	// PerClient vale para cada IP (antes da autenticação) e para cada cliente autenticado
+	PerClient domain.RateLimit
+	// Routes substitui PerClient nas rotas informadas (ex: "/weather")
+	Routes map[string]domain.RateLimit
+}
+
+// RateLimiter aplica token buckets por cliente, por rota e global
+type RateLimiter struct {
+	store  repository.RateLimitStore
+	config RateLimitConfig
+}
+
+func NewRateLimiter(store repository.RateLimitStore, config RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		store:  store,
+		config: config,
+	}
+}
+
+type rateLimitCheck struct {
+	key   string
+	limit domain.RateLimit
+}
+
+// IPMiddleware aplica os limites por IP e global antes da autenticação, para
+// que requisições recusadas (401) e tentativas de adivinhar API keys também
+// sejam contadas
+func (l *RateLimiter) IPMiddleware(next http.Handler) http.Handler {
+	return l.middleware(next, l.ipChecks)
+}
+
+// ClientMiddleware aplica o limite de cada cliente autenticado; deve rodar após
+// o Authenticator e não faz nada em requisições anônimas
+func (l *RateLimiter) ClientMiddleware(next http.Handler) http.Handler {
+	return l.middleware(next, l.clientChecks)
+}
+
+// middleware responde 429 quando algum dos limites é excedido. Falhas do store
+// não bloqueiam a requisição, para que uma indisponibilidade do Redis não derrube o gateway.
+func (l *RateLimiter) middleware(next http.Handler, checks func(*http.Request) []rateLimitCheck) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		span := trace.SpanFromContext(ctx)
+
+		var (
+			tightest *repository.RateLimitResult
+			denied   *repository.RateLimitResult
+		)
+		for _, check := range checks(r) {
+			result, err := l.store.Take(ctx, check.key, check.limit)
+			if err != nil {
+				log.Printf("Error checking rate limit, allowing request: %v", err)
+				span.AddEvent("rate limit store unavailable", trace.WithAttributes(attribute.String("error", err.Error())))
+				continue
+			}
+
+			if tightest == nil || result.Remaining < tightest.Remaining {
+				tightest = &result
+			}
+			if !result.Allowed {
+				denied = &result
+				span.SetAttributes(attribute.String("ratelimit.key", check.key))
+				break
+			}
+		}
+
+		// Os headers informam o limite mais apertado entre as duas etapas
+		if tightest != nil && !tighterHeader(w.Header(), tightest.Remaining) {
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(tightest.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(tightest.ResetAfter.Seconds())))
+		}
+
+		if denied != nil {
+			span.SetStatus(codes.Error, domain.ErrRateLimited.Error())
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(denied.RetryAfter.Seconds())))
+			writeProblemResponse(w, http.StatusTooManyRequests, domain.ErrRateLimited.Error())
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ipChecks lista os buckets anteriores à autenticação: primeiro o do IP, depois
+// o global, para que IPs já bloqueados não consumam fichas do limite global
+func (l *RateLimiter) ipChecks(r *http.Request) []rateLimitCheck {
+	checks := l.clientLimit(r, "ip:"+remoteIP(r))
+	if l.config.Global.Enabled() {
+		checks = append(checks, rateLimitCheck{key: "global", limit: l.config.Global})
+	}
+	return checks
+}
+
+func (l *RateLimiter) clientChecks(r *http.Request) []rateLimitCheck {
+	client, ok := ClientFromContext(r.Context())
+	if !ok {
+		return nil
+	}
+	return l.clientLimit(r, "client:"+client.ID)
+}
+
+// clientLimit é o bucket de key na rota (RATE_LIMIT_ROUTES) ou, sem limite
+// específico, o de PerClient
+func (l *RateLimiter) clientLimit(r *http.Request, key string) []rateLimitCheck {
+	route := routePattern(r)
+	if limit, ok := l.config.Routes[route]; ok && limit.Enabled() {
+		return []rateLimitCheck{{key: "route:" + route + ":" + key, limit: limit}}
+	}
+	if l.config.PerClient.Enabled() {
+		return []rateLimitCheck{{key: key, limit: l.config.PerClient}}
+	}
+	return nil
+}
+
+// tighterHeader indica se uma etapa anterior já informou um limite com menos fichas
+func tighterHeader(header http.Header, remaining int) bool {
+	previous, err := strconv.Atoi(header.Get("RateLimit-Remaining"))
+	return err == nil && previous <= remaining
+}
+
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return r.URL.Path
+}
+
+// remoteIP usa o endereço da conexão ou, atrás de um proxy confiável
+// (TRUSTED_PROXIES), o IP do cliente informado por ele (realIP)
+func remoteIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+func ceilSeconds(seconds float64) int {
+	return int(math.Ceil(seconds))
+}
+
+func writeProblemResponse(w http.ResponseWriter, statusCode int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(statusCode)
+
+	problem := dto.ProblemResponse{
+		Type:    "about:blank",
+		Title:   http.StatusText(statusCode),
+		Status:  statusCode,
+		Detail:  detail,
+		Message: detail,
+	}
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		log.Printf("Error writing problem response: %v", err)
+	}
+}
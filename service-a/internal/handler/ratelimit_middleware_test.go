@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockServiceBClient struct {
+	mock.Mock
+}
+
+func (m *MockServiceBClient) GetWeather(ctx context.Context, req dto.WeatherRequest) (*dto.WeatherResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.WeatherResponse), args.Error(1)
+}
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(context.Context, string, domain.RateLimit) (repository.RateLimitResult, error) {
+	return repository.RateLimitResult{}, errors.New("connection refused")
+}
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	perSecond := func(n int) domain.RateLimit { return domain.RateLimit{Requests: n, Period: time.Second} }
+
+	tests := []struct {
+		name              string
+		store             repository.RateLimitStore
+		config            RateLimitConfig
+		remoteAddrs       []string
+		expectedStatuses  []int
+		expectedRemaining string
+	}{
+		{
+			name:              "per client limit by ip",
+			config:            RateLimitConfig{PerClient: perSecond(1)},
+			remoteAddrs:       []string{"10.0.0.1:1234", "10.0.0.1:5678", "10.0.0.2:1234"},
+			expectedStatuses:  []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK},
+			expectedRemaining: "0",
+		},
+		{
+			name:              "route limit overrides per client limit",
+			config:            RateLimitConfig{PerClient: perSecond(1), Routes: map[string]domain.RateLimit{"/weather": perSecond(2)}},
+			remoteAddrs:       []string{"10.0.0.1:1", "10.0.0.1:2", "10.0.0.1:3"},
+			expectedStatuses:  []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			expectedRemaining: "0",
+		},
+		{
+			name:              "global limit shared by clients",
+			config:            RateLimitConfig{PerClient: perSecond(5), Global: perSecond(2)},
+			remoteAddrs:       []string{"10.0.0.1:1", "10.0.0.2:1", "10.0.0.3:1"},
+			expectedStatuses:  []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			expectedRemaining: "0",
+		},
+		{
+			name:             "store failure allows request",
+			store:            failingRateLimitStore{},
+			config:           RateLimitConfig{Global: perSecond(1)},
+			remoteAddrs:      []string{"10.0.0.1:1", "10.0.0.1:1"},
+			expectedStatuses: []int{http.StatusOK, http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockServiceBClient)
+			mockClient.On("GetWeather", mock.Anything, dto.WeatherRequest{CEP: "26140040"}).Return(&dto.WeatherResponse{City: "Belford Roxo"}, nil)
+
+			store := tt.store
+			if store == nil {
+				store = repository.NewInMemoryRateLimitStore()
+			}
+			limiter := NewRateLimiter(store, tt.config)
+			router := NewWeatherHandler(mockClient, WithMiddleware(limiter.IPMiddleware)).SetupRoutes()
+
+			var rec *httptest.ResponseRecorder
+			for i, remoteAddr := range tt.remoteAddrs {
+				req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(`{"cep":"26140040"}`))
+				req.RemoteAddr = remoteAddr
+				rec = httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				assert.Equal(t, tt.expectedStatuses[i], rec.Code, "request %d", i)
+				if rec.Code == http.StatusTooManyRequests {
+					assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+					assert.JSONEq(t, `{"type":"about:blank","title":"Too Many Requests","status":429,"detail":"rate limit exceeded","message":"rate limit exceeded"}`, rec.Body.String())
+					assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+					assert.Equal(t, tt.expectedRemaining, rec.Header().Get("RateLimit-Remaining"))
+				}
+				if tt.store == nil {
+					assert.NotEmpty(t, rec.Header().Get("RateLimit-Limit"))
+					assert.NotEmpty(t, rec.Header().Get("RateLimit-Reset"))
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterClientIP(t *testing.T) {
+	type request struct {
+		remoteAddr string
+		forwarded  string
+		realIP     string
+	}
+	tests := []struct {
+		name             string
+		trustedProxies   []netip.Prefix
+		requests         []request
+		expectedStatuses []int
+	}{
+		{
+			name: "forwarded headers ignored without trusted proxies",
+			requests: []request{
+				{remoteAddr: "203.0.113.7:1", forwarded: "198.51.100.1"},
+				{remoteAddr: "203.0.113.7:2", forwarded: "198.51.100.2"},
+				{remoteAddr: "203.0.113.7:3", realIP: "198.51.100.3"},
+			},
+			expectedStatuses: []int{http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests},
+		},
+		{
+			name:           "forwarded headers ignored from untrusted peers",
+			trustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			requests: []request{
+				{remoteAddr: "203.0.113.7:1", forwarded: "198.51.100.1"},
+				{remoteAddr: "203.0.113.7:2", forwarded: "198.51.100.2"},
+			},
+			expectedStatuses: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:           "client behind trusted proxy",
+			trustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			requests: []request{
+				{remoteAddr: "10.0.0.1:1", forwarded: "198.51.100.1"},
+				{remoteAddr: "10.0.0.2:1", forwarded: "198.51.100.2"},
+				{remoteAddr: "10.0.0.1:2", realIP: "198.51.100.1"},
+			},
+			expectedStatuses: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:           "forged leftmost entries ignored",
+			trustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			requests: []request{
+				{remoteAddr: "10.0.0.1:1", forwarded: "192.0.2.1, 198.51.100.1, 10.0.0.9"},
+				{remoteAddr: "10.0.0.1:2", forwarded: "192.0.2.2, 198.51.100.1, 10.0.0.9"},
+			},
+			expectedStatuses: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockServiceBClient)
+			mockClient.On("GetWeather", mock.Anything, dto.WeatherRequest{CEP: "26140040"}).Return(&dto.WeatherResponse{City: "Belford Roxo"}, nil)
+
+			limiter := NewRateLimiter(repository.NewInMemoryRateLimitStore(), RateLimitConfig{PerClient: domain.RateLimit{Requests: 1, Period: time.Minute}})
+			router := NewWeatherHandler(mockClient,
+				WithMiddleware(limiter.IPMiddleware),
+				WithTrustedProxies(tt.trustedProxies...),
+			).SetupRoutes()
+
+			for i, r := range tt.requests {
+				req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(`{"cep":"26140040"}`))
+				req.RemoteAddr = r.remoteAddr
+				if r.forwarded != "" {
+					req.Header.Set("X-Forwarded-For", r.forwarded)
+				}
+				if r.realIP != "" {
+					req.Header.Set("X-Real-IP", r.realIP)
+				}
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				assert.Equal(t, tt.expectedStatuses[i], rec.Code, "request %d", i)
+			}
+		})
+	}
+}
+
+func TestRateLimiterUsesAuthenticatedClient(t *testing.T) {
+	mockClient := new(MockServiceBClient)
+	mockClient.On("GetWeather", mock.Anything, dto.WeatherRequest{CEP: "26140040"}).Return(&dto.WeatherResponse{City: "Belford Roxo"}, nil)
+
+	keys := repository.NewInMemoryAPIKeyStore(map[string]domain.Client{"key-a": {ID: "a"}, "key-b": {ID: "b"}})
+	authenticator := NewAuthenticator(keys, repository.NewInMemoryQuotaStore(time.Hour))
+	limiter := NewRateLimiter(repository.NewInMemoryRateLimitStore(), RateLimitConfig{PerClient: domain.RateLimit{Requests: 1, Period: time.Minute}})
+	router := NewWeatherHandler(mockClient, WithMiddleware(authenticator.Middleware, limiter.ClientMiddleware)).SetupRoutes()
+
+	// Mesmo IP, clientes diferentes: buckets separados
+	for _, tc := range []struct {
+		key      string
+		expected int
+	}{{"key-a", http.StatusOK}, {"key-b", http.StatusOK}, {"key-a", http.StatusTooManyRequests}} {
+		req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(`{"cep":"26140040"}`))
+		req.Header.Set(HeaderAPIKey, tc.key)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, tc.expected, rec.Code)
+	}
+}
+
+func TestRateLimiterCountsUnauthenticatedRequests(t *testing.T) {
+	mockClient := new(MockServiceBClient)
+	mockClient.On("GetWeather", mock.Anything, dto.WeatherRequest{CEP: "26140040"}).Return(&dto.WeatherResponse{City: "Belford Roxo"}, nil)
+
+	keys := repository.NewInMemoryAPIKeyStore(map[string]domain.Client{"key-a": {ID: "a"}})
+	authenticator := NewAuthenticator(keys, repository.NewInMemoryQuotaStore(time.Hour))
+	limiter := NewRateLimiter(repository.NewInMemoryRateLimitStore(), RateLimitConfig{
+		PerClient: domain.RateLimit{Requests: 2, Period: time.Minute},
+		Global:    domain.RateLimit{Requests: 3, Period: time.Minute},
+	})
+	router := NewWeatherHandler(mockClient, WithMiddleware(limiter.IPMiddleware, authenticator.Middleware, limiter.ClientMiddleware)).SetupRoutes()
+
+	// Chaves inválidas consomem o limite do IP e o global antes da autenticação
+	for _, tc := range []struct {
+		key        string
+		remoteAddr string
+		expected   int
+	}{
+		{"guess-1", "10.0.0.1:1", http.StatusUnauthorized},
+		{"guess-2", "10.0.0.1:1", http.StatusUnauthorized},
+		{"key-a", "10.0.0.1:1", http.StatusTooManyRequests},
+		{"key-a", "10.0.0.2:1", http.StatusOK},
+		{"key-a", "10.0.0.3:1", http.StatusTooManyRequests},
+	} {
+		req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(`{"cep":"26140040"}`))
+		req.Header.Set(HeaderAPIKey, tc.key)
+		req.RemoteAddr = tc.remoteAddr
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, tc.expected, rec.Code, "%s from %s", tc.key, tc.remoteAddr)
+	}
+}
+
+func TestRateLimiterReportsTightestLimit(t *testing.T) {
+	mockClient := new(MockServiceBClient)
+	mockClient.On("GetWeather", mock.Anything, dto.WeatherRequest{CEP: "26140040"}).Return(&dto.WeatherResponse{City: "Belford Roxo"}, nil)
+
+	keys := repository.NewInMemoryAPIKeyStore(map[string]domain.Client{"key-a": {ID: "a"}})
+	authenticator := NewAuthenticator(keys, repository.NewInMemoryQuotaStore(time.Hour))
+	limiter := NewRateLimiter(repository.NewInMemoryRateLimitStore(), RateLimitConfig{
+		PerClient: domain.RateLimit{Requests: 5, Period: time.Minute},
+		Global:    domain.RateLimit{Requests: 2, Period: time.Minute},
+	})
+	router := NewWeatherHandler(mockClient, WithMiddleware(limiter.IPMiddleware, authenticator.Middleware, limiter.ClientMiddleware)).SetupRoutes()
+
+	req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(`{"cep":"26140040"}`))
+	req.Header.Set(HeaderAPIKey, "key-a")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	// O bucket do cliente (4 restantes) não sobrescreve o global (1 restante)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+}
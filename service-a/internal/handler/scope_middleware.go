@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeAuthorizer exige que o cliente autenticado tenha os scopes de cada rota.
+// Padrões terminados em "/*" cobrem todas as subrotas (ex: "/admin/*").
+type ScopeAuthorizer struct {
+	routes map[string][]string
+}
+
+func NewScopeAuthorizer(routes map[string][]string) *ScopeAuthorizer {
+	return &ScopeAuthorizer{routes: routes}
+}
+
+// Middleware responde 403 quando faltam scopes; deve rodar após o Authenticator
+func (a *ScopeAuthorizer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		required := a.requiredScopes(routePattern(r))
+		if len(required) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		client, ok := ClientFromContext(r.Context())
+		if !ok {
+			writeErrorResponse(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		if !client.HasScopes(required...) {
+			trace.SpanFromContext(r.Context()).SetStatus(codes.Error, domain.ErrForbidden.Error())
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="service-a", error="insufficient_scope", scope=%q`, strings.Join(required, " ")))
+			writeErrorResponse(w, http.StatusForbidden, "forbidden")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *ScopeAuthorizer) requiredScopes(route string) []string {
+	if scopes, ok := a.routes[route]; ok {
+		return scopes
+	}
+
+	for pattern, scopes := range a.routes {
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(route, prefix+"/") {
+			return scopes
+		}
+	}
+	return nil
+}
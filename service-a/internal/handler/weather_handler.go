@@ -1,11 +1,14 @@
 package handler
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"log"
+	"net"
 	"net/http"
+	"net/netip"
 
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/decode"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/repository"
@@ -13,19 +16,54 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type WeatherHandler struct {
 	serviceBClient repository.ServiceBClient
 	tracer         trace.Tracer
+	decoder        *decode.Decoder
+	middlewares    chi.Middlewares
+	trustedProxies []netip.Prefix
 }
 
-func NewWeatherHandler(serviceBClient repository.ServiceBClient) *WeatherHandler {
-	return &WeatherHandler{
+// Option customiza o WeatherHandler
+type Option func(*WeatherHandler)
+
+// WithMiddleware adiciona middlewares executados dentro do span da requisição,
+// antes do handler, na ordem informada
+func WithMiddleware(middlewares ...func(http.Handler) http.Handler) Option {
+	return func(h *WeatherHandler) {
+		h.middlewares = append(h.middlewares, middlewares...)
+	}
+}
+
+// WithDecoder substitui o decoder padrão dos bodies JSON (limite de tamanho e campos desconhecidos)
+func WithDecoder(decoder *decode.Decoder) Option {
+	return func(h *WeatherHandler) {
+		h.decoder = decoder
+	}
+}
+
+// WithTrustedProxies define os proxies (TRUSTED_PROXIES) cujos X-Forwarded-For
+// e X-Real-IP identificam o cliente; sem eles vale o endereço da conexão
+func WithTrustedProxies(proxies ...netip.Prefix) Option {
+	return func(h *WeatherHandler) {
+		h.trustedProxies = append(h.trustedProxies, proxies...)
+	}
+}
+
+func NewWeatherHandler(serviceBClient repository.ServiceBClient, opts ...Option) *WeatherHandler {
+	h := &WeatherHandler{
 		serviceBClient: serviceBClient,
 		tracer:         otel.Tracer("service-a"),
+		decoder:        decode.New(),
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	return h
 }
 
 func (h *WeatherHandler) SetupRoutes() *chi.Mux {
@@ -34,10 +72,11 @@ func (h *WeatherHandler) SetupRoutes() *chi.Mux {
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(realIP(h.trustedProxies))
 
-	// Instrumenta com OpenTelemetry
-	r.Post("/weather", otelhttp.NewHandler(http.HandlerFunc(h.GetWeather), "service-a.handle-request").ServeHTTP)
+	// Instrumenta com OpenTelemetry; os middlewares do handler rodam dentro do span
+	weather := h.middlewares.Handler(withBaggage(http.HandlerFunc(h.GetWeather)))
+	r.Post("/weather", otelhttp.NewHandler(weather, "service-a.handle-request").ServeHTTP)
 
 	return r
 }
@@ -46,67 +85,113 @@ func (h *WeatherHandler) SetupRoutes() *chi.Mux {
 func (h *WeatherHandler) GetWeather(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Cria o span para validação
-	ctx, span := h.tracer.Start(ctx, "service-a.validate-input")
-	defer span.End()
+	// Recusa antes do processamento se nenhum formato de resposta for aceitável
+	if _, _, ok := negotiate(r.Header.Get("Accept")); !ok {
+		h.writeErrorResponse(w, http.StatusNotAcceptable, "not acceptable")
+		return
+	}
 
-	// Parsea o body
+	// Valida a entrada em um span próprio
 	var req dto.WeatherRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		span.RecordError(err)
-		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body")
+	err := h.withSpan(ctx, "service-a.validate-input", func(context.Context) error {
+		return h.decodeRequest(w, r, &req)
+	})
+	if err != nil {
+		h.handleServiceError(w, r, err)
 		return
 	}
 
-	// Valida o CEP
-	if err := domain.ValidateZipcode(req.CEP); err != nil {
-		span.RecordError(err)
-		h.writeErrorResponse(w, http.StatusUnprocessableEntity, "invalid zipcode")
+	// Chama o Serviço B em um span irmão do de validação
+	var weather *dto.WeatherResponse
+	err = h.withSpan(ctx, "service-a.call-service-b", func(ctx context.Context) error {
+		var err error
+		weather, err = h.serviceBClient.GetWeather(ctx, req)
+		return err
+	})
+	if err != nil {
+		log.Printf("Error calling service B: %v", err)
+		h.handleServiceError(w, r, err)
 		return
 	}
 
-	span.End() // Fecha o span de validação
+	// Retorna sucesso no formato negociado pelo Accept
+	h.writeResponse(w, r, http.StatusOK, weather)
+}
 
-	// Cria o span para chamada ao Serviço B
-	ctx, span = h.tracer.Start(ctx, "service-a.call-service-b")
+// withSpan executa fn em um span filho de ctx, registrando o erro e o status
+// antes de encerrá-lo. Cada span é encerrado exatamente uma vez.
+func (h *WeatherHandler) withSpan(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	ctx, span := h.tracer.Start(ctx, name)
 	defer span.End()
 
-	// Chama o Serviço B
-	weather, err := h.serviceBClient.GetWeather(ctx, req.CEP)
-	if err != nil {
+	if err := fn(ctx); err != nil {
 		span.RecordError(err)
-		log.Printf("Error calling service B: %v", err)
-		h.handleServiceError(w, err)
-		return
+		span.SetStatus(codes.Error, err.Error())
+		return err
 	}
 
-	// Retorna sucesso
-	h.writeJSONResponse(w, http.StatusOK, weather)
+	return nil
 }
 
-func (h *WeatherHandler) writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+// decodeRequest parseia o body e valida o CEP e as opções de conversão
+func (h *WeatherHandler) decodeRequest(w http.ResponseWriter, r *http.Request, req *dto.WeatherRequest) error {
+	if err := h.decoder.JSON(w, r, req); err != nil {
+		var decodeErr *decode.Error
+		if !errors.As(err, &decodeErr) {
+			return err
+		}
+		// Falhas no CEP mantêm a mensagem do contrato
+		if decodeErr.Status == http.StatusUnprocessableEntity && hasField(decodeErr.Fields, "cep") {
+			return &domain.ServiceError{Err: err, StatusCode: http.StatusUnprocessableEntity, Message: "invalid zipcode"}
+		}
+		return &domain.ServiceError{Err: err, StatusCode: decodeErr.Status, Message: decodeErr.Message}
+	}
+
+	if err := domain.ValidateZipcode(req.CEP); err != nil {
+		return &domain.ServiceError{Err: err, StatusCode: http.StatusUnprocessableEntity, Message: "invalid zipcode"}
+	}
+
+	return nil
+}
 
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("Error writing JSON response: %v", err)
+func hasField(fields []decode.FieldError, name string) bool {
+	for _, field := range fields {
+		if field.Field == name {
+			return true
+		}
 	}
+	return false
+}
+
+func (h *WeatherHandler) writeResponse(w http.ResponseWriter, r *http.Request, statusCode int, data interface{}) {
+	writeResponse(w, r, statusCode, data)
 }
 
 func (h *WeatherHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
-	errorResp := dto.ErrorResponse{
-		Message: message,
-	}
-	h.writeJSONResponse(w, statusCode, errorResp)
+	writeErrorResponse(w, statusCode, message)
 }
 
-// Trata erros do Service B preservando o status code
-func (h *WeatherHandler) handleServiceError(w http.ResponseWriter, err error) {
+// Trata erros do Service B preservando o status code, no formato negociado
+func (h *WeatherHandler) handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
 	var serviceErr *domain.ServiceError
 	if ok := errors.As(err, &serviceErr); ok {
-		h.writeErrorResponse(w, serviceErr.StatusCode, serviceErr.Message)
+		// Erros de decodificação detalham os campos inválidos
+		resp := dto.ErrorResponse{Message: serviceErr.Message}
+		var decodeErr *decode.Error
+		if errors.As(err, &decodeErr) {
+			resp.Errors = decodeErr.Fields
+		}
+		h.writeResponse(w, r, serviceErr.StatusCode, resp)
+		return
+	}
+
+	// O prazo da requisição (REQUEST_TIMEOUT) ou o timeout do cliente acabou
+	// antes da resposta do Service B
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		h.writeResponse(w, r, http.StatusGatewayTimeout, dto.ErrorResponse{Message: "deadline exceeded"})
 		return
 	}
 
-	h.writeErrorResponse(w, http.StatusInternalServerError, "internal server error")
+	h.writeResponse(w, r, http.StatusInternalServerError, dto.ErrorResponse{Message: "internal server error"})
 }
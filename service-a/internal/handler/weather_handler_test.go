@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/deadline"
+	telemetry "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel/oteltest"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWeatherHandlerGetWeatherSpans(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceBStatus int
+		serviceBBody   string
+		serviceBDown   bool
+		expectedStatus int
+		expectedBody   string
+		expectedSpans  []string
+		expectServiceB bool
+	}{
+		{
+			name:           "success",
+			body:           `{"cep":"26140040"}`,
+			serviceBStatus: http.StatusOK,
+			serviceBBody:   `{"city":"Belford Roxo","temp_C":25.5,"temp_F":77.9,"temp_K":298.5}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"city":"Belford Roxo","temp_C":25.5,"temp_F":77.9,"temp_K":298.5}`,
+			expectedSpans: []string{
+				"service-a.handle-request <- root : " + codes.Unset.String(),
+				"service-a.validate-input <- service-a.handle-request : " + codes.Unset.String(),
+				"service-a.call-service-b <- service-a.handle-request : " + codes.Unset.String(),
+				"HTTP POST <- service-a.call-service-b : " + codes.Unset.String(),
+			},
+			expectServiceB: true,
+		},
+		{
+			name:           "error - invalid request body",
+			body:           `{"cep":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"message":"invalid request body"}`,
+			expectedSpans: []string{
+				"service-a.handle-request <- root : " + codes.Unset.String(),
+				"service-a.validate-input <- service-a.handle-request : " + codes.Error.String(),
+			},
+		},
+		{
+			name:           "error - invalid zipcode",
+			body:           `{"cep":"123"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"message":"invalid zipcode","errors":[{"field":"cep","message":"must have exactly 8 characters"}]}`,
+			expectedSpans: []string{
+				"service-a.handle-request <- root : " + codes.Unset.String(),
+				"service-a.validate-input <- service-a.handle-request : " + codes.Error.String(),
+			},
+		},
+		{
+			name:           "error - non numeric zipcode",
+			body:           `{"cep":"1234567a"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"message":"invalid zipcode"}`,
+			expectedSpans: []string{
+				"service-a.handle-request <- root : " + codes.Unset.String(),
+				"service-a.validate-input <- service-a.handle-request : " + codes.Error.String(),
+			},
+		},
+		{
+			name:           "error - unknown field",
+			body:           `{"cep":"26140040","city":"Belford Roxo"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"message":"invalid request body","errors":[{"field":"city","message":"unknown field"}]}`,
+			expectedSpans: []string{
+				"service-a.handle-request <- root : " + codes.Unset.String(),
+				"service-a.validate-input <- service-a.handle-request : " + codes.Error.String(),
+			},
+		},
+		{
+			name:           "error - trailing data",
+			body:           `{"cep":"26140040"}{"cep":"01310100"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"message":"invalid request body"}`,
+			expectedSpans: []string{
+				"service-a.handle-request <- root : " + codes.Unset.String(),
+				"service-a.validate-input <- service-a.handle-request : " + codes.Error.String(),
+			},
+		},
+		{
+			name:           "error - zipcode not found in service B",
+			body:           `{"cep":"99999999"}`,
+			serviceBStatus: http.StatusNotFound,
+			serviceBBody:   `{"message":"can not find zipcode"}`,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"message":"can not find zipcode"}`,
+			expectedSpans: []string{
+				"service-a.handle-request <- root : " + codes.Unset.String(),
+				"service-a.validate-input <- service-a.handle-request : " + codes.Unset.String(),
+				"service-a.call-service-b <- service-a.handle-request : " + codes.Error.String(),
+				"HTTP POST <- service-a.call-service-b : " + codes.Error.String(),
+			},
+			expectServiceB: true,
+		},
+		{
+			name:           "error - service B internal error",
+			body:           `{"cep":"26140040"}`,
+			serviceBStatus: http.StatusInternalServerError,
+			serviceBBody:   `{"message":"internal server error"}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"message":"internal server error"}`,
+			expectedSpans: []string{
+				"service-a.handle-request <- root : " + codes.Error.String(),
+				"service-a.validate-input <- service-a.handle-request : " + codes.Unset.String(),
+				"service-a.call-service-b <- service-a.handle-request : " + codes.Error.String(),
+				"HTTP POST <- service-a.call-service-b : " + codes.Error.String(),
+			},
+			expectServiceB: true,
+		},
+		{
+			name:           "error - service B unreachable",
+			body:           `{"cep":"26140040"}`,
+			serviceBDown:   true,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"message":"internal server error"}`,
+			expectedSpans: []string{
+				"service-a.handle-request <- root : " + codes.Error.String(),
+				"service-a.validate-input <- service-a.handle-request : " + codes.Unset.String(),
+				"service-a.call-service-b <- service-a.handle-request : " + codes.Error.String(),
+				"HTTP POST <- service-a.call-service-b : " + codes.Error.String(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Registrar um recorder de spans em memória
+			recorder := oteltest.Record(t)
+
+			// Criar servidor mock do Service B
+			serviceBCalled := false
+			serviceB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				serviceBCalled = true
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.serviceBStatus)
+				w.Write([]byte(tt.serviceBBody))
+			}))
+			if tt.serviceBDown {
+				serviceB.Close()
+			} else {
+				defer serviceB.Close()
+			}
+
+			// Criar handler com cliente real apontando para o mock
+			handler := NewWeatherHandler(repository.NewServiceBClient(serviceB.URL))
+			router := handler.SetupRoutes()
+
+			// Executar requisição
+			req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			// Verificar resposta
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			assert.Equal(t, tt.expectServiceB, serviceBCalled)
+
+			// Verificar árvore de spans
+			spans := recorder.Ended()
+			assert.ElementsMatch(t, tt.expectedSpans, oteltest.SpanTree(spans))
+			assert.Len(t, recorder.Started(), len(spans), "every started span must be ended")
+			for _, s := range spans {
+				assert.Equal(t, spans[0].SpanContext().TraceID(), s.SpanContext().TraceID())
+			}
+		})
+	}
+}
+
+func TestWeatherHandlerPropagatesContextToServiceB(t *testing.T) {
+	recorder := oteltest.Record(t)
+	propagator, err := telemetry.NewPropagator(nil)
+	assert.NoError(t, err)
+	otel.SetTextMapPropagator(propagator)
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	// Service B mock extrai o contexto recebido
+	var received context.Context
+	serviceB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = propagator.Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+		w.Write([]byte(`{"city":"Belford Roxo","temp_C":25.5,"temp_F":77.9,"temp_K":298.5}`))
+	}))
+	defer serviceB.Close()
+
+	router := NewWeatherHandler(repository.NewServiceBClient(serviceB.URL)).SetupRoutes()
+
+	req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(`{"cep":"26140040"}`))
+	req.Header.Set(HeaderClientID, "client-42")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// O trace e o baggage devem chegar ao Service B
+	assert.Equal(t, recorder.Ended()[0].SpanContext().TraceID(), trace.SpanContextFromContext(received).TraceID())
+	bag := baggage.FromContext(received)
+	assert.Equal(t, "client-42", bag.Member(telemetry.BaggageClientID).Value())
+	assert.Equal(t, "http", bag.Member(telemetry.BaggageRequestSource).Value())
+
+	// E também ficam registrados no span da requisição
+	for _, s := range recorder.Ended() {
+		if s.Name() == "service-a.handle-request" {
+			assert.Contains(t, s.Attributes(), attribute.String(telemetry.BaggageClientID, "client-42"))
+		}
+	}
+}
+
+func TestWeatherHandlerDropsInboundBaggage(t *testing.T) {
+	propagator, err := telemetry.NewPropagator(nil)
+	assert.NoError(t, err)
+	otel.SetTextMapPropagator(propagator)
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	var received context.Context
+	serviceB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = propagator.Extract(context.Background(), propagation.HeaderCarrier(r.Header))
+		w.Write([]byte(`{"city":"Belford Roxo","temp_C":25.5,"temp_F":77.9,"temp_K":298.5}`))
+	}))
+	defer serviceB.Close()
+
+	router := NewWeatherHandler(repository.NewServiceBClient(serviceB.URL)).SetupRoutes()
+
+	// O chamador tenta forjar o client ID e injetar outros membros no baggage
+	req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(`{"cep":"26140040"}`))
+	req.Header.Set("baggage", "client.id=forged,tenant=other")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	bag := baggage.FromContext(received)
+	assert.Empty(t, bag.Member(telemetry.BaggageClientID).Value())
+	assert.Empty(t, bag.Member("tenant").Value())
+	assert.Equal(t, "http", bag.Member(telemetry.BaggageRequestSource).Value())
+}
+
+func TestWeatherHandlerDeadlineExceeded(t *testing.T) {
+	// Service B responde depois do prazo da requisição
+	budgets := make(chan string, 1)
+	release := make(chan struct{})
+	serviceB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		budgets <- r.Header.Get(deadline.Header)
+		<-release
+	}))
+	defer serviceB.Close()
+	defer close(release)
+
+	router := NewWeatherHandler(repository.NewServiceBClient(serviceB.URL),
+		WithMiddleware(deadline.Middleware(100*time.Millisecond, 0)),
+	).SetupRoutes()
+
+	req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(`{"cep":"26140040"}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	assert.JSONEq(t, `{"message":"deadline exceeded"}`, rec.Body.String())
+	budget := <-budgets
+	remaining, ok := deadline.Parse(budget)
+	assert.True(t, ok, budget)
+	assert.LessOrEqual(t, remaining, 100*time.Millisecond)
+}
+
+func TestWeatherHandlerForwardsConversionOptions(t *testing.T) {
+	precision := 2
+	expectedReq := dto.WeatherRequest{CEP: "26140040", Units: []string{"R", "K"}, Precision: &precision, ScientificKelvin: true}
+	weather := &dto.WeatherResponse{City: "Belford Roxo", TempK: proto.Float64(298.65), TempR: proto.Float64(537.57)}
+
+	tests := []struct {
+		name         string
+		accept       string
+		expectedBody string
+	}{
+		{name: "json omits scales not requested", accept: "application/json", expectedBody: `{"city":"Belford Roxo","temp_K":298.65,"temp_R":537.57}` + "\n"},
+		{name: "csv has a column per requested scale", accept: "text/csv", expectedBody: "city,temp_K,temp_R\nBelford Roxo,298.65,537.57\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockServiceBClient)
+			mockClient.On("GetWeather", mock.Anything, expectedReq).Return(weather, nil)
+			router := NewWeatherHandler(mockClient).SetupRoutes()
+
+			body := `{"cep":"26140040","units":["R","K"],"precision":2,"scientific_kelvin":true}`
+			req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
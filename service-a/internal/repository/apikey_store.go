@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+)
+
+// APIKeyStore resolve o cliente dono de uma API key
+type APIKeyStore interface {
+	FindByKey(ctx context.Context, key string) (*domain.Client, error)
+}
+
+// apiKeyEntry é o formato de cada cliente no arquivo de API keys.
+// Apenas o hash SHA-256 da chave é armazenado.
+type apiKeyEntry struct {
+	ClientID  string   `json:"client_id"`
+	Name      string   `json:"name"`
+	KeySHA256 string   `json:"key_sha256"`
+	Quota     int64    `json:"quota"`
+	Scopes    []string `json:"scopes"`
+}
+
+type apiKeyStore struct {
+	clients map[string]domain.Client
+}
+
+// HashAPIKey retorna o hash hexadecimal usado para indexar as chaves
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewInMemoryAPIKeyStore cria um store a partir de chaves em texto puro
+func NewInMemoryAPIKeyStore(keys map[string]domain.Client) APIKeyStore {
+	clients := make(map[string]domain.Client, len(keys))
+	for key, client := range keys {
+		clients[HashAPIKey(key)] = client
+	}
+	return &apiKeyStore{clients: clients}
+}
+
+// NewFileAPIKeyStore carrega os clientes de um arquivo JSON com os hashes das chaves
+func NewFileAPIKeyStore(path string) (APIKeyStore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading API keys file: %w", err)
+	}
+
+	var entries []apiKeyEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("error parsing API keys file: %w", err)
+	}
+
+	clients := make(map[string]domain.Client, len(entries))
+	for i, entry := range entries {
+		if entry.ClientID == "" || len(entry.KeySHA256) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid API key entry at index %d: client_id and a hex key_sha256 are required", i)
+		}
+		clients[entry.KeySHA256] = domain.Client{
+			ID:     entry.ClientID,
+			Name:   entry.Name,
+			Quota:  entry.Quota,
+			Scopes: entry.Scopes,
+		}
+	}
+
+	return &apiKeyStore{clients: clients}, nil
+}
+
+func (s *apiKeyStore) FindByKey(ctx context.Context, key string) (*domain.Client, error) {
+	if key == "" {
+		return nil, domain.ErrUnauthorized
+	}
+
+	client, ok := s.clients[HashAPIKey(key)]
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	return &client, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileAPIKeyStoreFindByKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-keys.json")
+	content := `[{"client_id":"ops","name":"Ops","key_sha256":"` + HashAPIKey("secret-key") + `","quota":100}]`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	store, err := NewFileAPIKeyStore(path)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		key         string
+		expected    *domain.Client
+		expectedErr error
+	}{
+		{"valid key", "secret-key", &domain.Client{ID: "ops", Name: "Ops", Quota: 100}, nil},
+		{"unknown key", "other-key", nil, domain.ErrUnauthorized},
+		{"empty key", "", nil, domain.ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := store.FindByKey(context.Background(), tt.key)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, client)
+		})
+	}
+}
+
+func TestNewFileAPIKeyStoreErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid json", `{`},
+		{"missing client id", `[{"key_sha256":"` + HashAPIKey("k") + `"}]`},
+		{"plain text key", `[{"client_id":"ops","key_sha256":"secret-key"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "api-keys.json")
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			_, err := NewFileAPIKeyStore(path)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := NewFileAPIKeyStore(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/contract"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// contractPath é o contrato publicado para o Service B, verificado em service-b/tests/contract
+var contractPath = filepath.Join("..", "..", "..", "contracts", "service-a-service-b.json")
+
+// go test ./internal/repository -run Contract -update-contract republica o contrato
+var updateContract = flag.Bool("update-contract", false, "publish the service-a -> service-b contract")
+
+func weatherRequest(cep string) contract.Request {
+	return weatherRequestWithBody(`{"cep":"` + cep + `"}`)
+}
+
+func weatherRequestWithBody(body string) contract.Request {
+	return contract.Request{
+		Method:  "POST",
+		Path:    "/weather",
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    json.RawMessage(body),
+	}
+}
+
+func errorResponse(status int, message string) contract.Response {
+	return contract.Response{
+		Status:  status,
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    json.RawMessage(`{"message":"` + message + `"}`),
+		// O Service A repassa a mensagem ao usuário, então ela faz parte do contrato
+		Exact: []string{"message"},
+	}
+}
+
+// TestServiceBClientContract define as interações de que o serviceBClient
+// depende, verifica o cliente contra elas e confere o contrato publicado
+func TestServiceBClientContract(t *testing.T) {
+	tests := []struct {
+		interaction     contract.Interaction
+		expected        *dto.WeatherResponse
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			interaction: contract.Interaction{
+				Description:   "weather for a known zipcode",
+				ProviderState: "zipcode 26140040 is in Belford Roxo",
+				Request:       weatherRequest("26140040"),
+				Response: contract.Response{
+					Status:  http.StatusOK,
+					Headers: map[string]string{"Content-Type": "application/json"},
+					Body:    json.RawMessage(`{"city":"Belford Roxo","temp_C":25.5,"temp_F":77.9,"temp_K":298.5}`),
+					Exact:   []string{"city"},
+				},
+			},
+			expected: &dto.WeatherResponse{City: "Belford Roxo", TempC: proto.Float64(25.5), TempF: proto.Float64(77.9), TempK: proto.Float64(298.5)},
+		},
+		{
+			interaction: contract.Interaction{
+				Description:   "weather in the requested units",
+				ProviderState: "zipcode 26140040 is in Belford Roxo",
+				Request:       weatherRequestWithBody(`{"cep":"26140040","units":["R","K"],"precision":2,"scientific_kelvin":true}`),
+				Response: contract.Response{
+					Status:  http.StatusOK,
+					Headers: map[string]string{"Content-Type": "application/json"},
+					Body:    json.RawMessage(`{"city":"Belford Roxo","temp_R":537.57,"temp_K":298.65}`),
+					Exact:   []string{"city"},
+				},
+			},
+			expected: &dto.WeatherResponse{City: "Belford Roxo", TempK: proto.Float64(298.65), TempR: proto.Float64(537.57)},
+		},
+		{
+			interaction: contract.Interaction{
+				Description: "invalid zipcode",
+				Request:     weatherRequest("1234567a"),
+				Response:    errorResponse(http.StatusUnprocessableEntity, "invalid zipcode"),
+			},
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedMessage: "invalid zipcode",
+		},
+		{
+			interaction: contract.Interaction{
+				Description:   "unknown zipcode",
+				ProviderState: "zipcode 99999999 does not exist",
+				Request:       weatherRequest("99999999"),
+				Response:      errorResponse(http.StatusNotFound, "can not find zipcode"),
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "can not find zipcode",
+		},
+		{
+			interaction: contract.Interaction{
+				Description:   "weather provider failure",
+				ProviderState: "weather provider is failing",
+				Request:       weatherRequest("26140040"),
+				Response:      errorResponse(http.StatusInternalServerError, "internal server error"),
+			},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "internal server error",
+		},
+	}
+
+	published := &contract.Contract{Consumer: "service-a", Provider: "service-b"}
+	for _, tt := range tests {
+		published.Interactions = append(published.Interactions, tt.interaction)
+
+		t.Run(tt.interaction.Description, func(t *testing.T) {
+			serviceB := contract.NewMockServer(tt.interaction)
+			defer serviceB.Close()
+
+			weather, err := NewServiceBClient(serviceB.URL).GetWeather(context.Background(), requestOf(t, tt.interaction))
+
+			require.NoError(t, serviceB.Err())
+			if tt.expectedStatus == 0 {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, weather)
+				return
+			}
+			var serviceErr *domain.ServiceError
+			require.True(t, errors.As(err, &serviceErr))
+			assert.Equal(t, tt.expectedStatus, serviceErr.StatusCode)
+			assert.Equal(t, tt.expectedMessage, serviceErr.Message)
+		})
+	}
+
+	if *updateContract {
+		require.NoError(t, published.Write(contractPath))
+	}
+	expected, err := published.Marshal()
+	require.NoError(t, err)
+	actual, err := os.ReadFile(contractPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual), "contract is outdated: run go test ./internal/repository -run Contract -update-contract")
+}
+
+func requestOf(t *testing.T, interaction contract.Interaction) dto.WeatherRequest {
+	t.Helper()
+	var req dto.WeatherRequest
+	require.NoError(t, json.Unmarshal(interaction.Request.Body, &req))
+	return req
+}
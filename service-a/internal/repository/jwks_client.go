@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrKeyNotFound indica que o kid do token não está no JWKS do emissor
+var ErrKeyNotFound = errors.New("signing key not found")
+
+// JWKSClient resolve as chaves públicas de assinatura do provedor de identidade
+type JWKSClient interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwksClient struct {
+	url        string
+	ttl        time.Duration
+	minRefresh time.Duration
+	httpClient *http.Client
+	tracer     trace.Tracer
+	now        func() time.Time
+
+	mu         sync.Mutex
+	keys       map[string]crypto.PublicKey
+	fetchedAt  time.Time
+	refreshing *jwksRefresh
+}
+
+// jwksRefresh é uma busca do JWKS em andamento; err vale após done fechar
+type jwksRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// NewJWKSClient cria um cliente que mantém o JWKS em cache por ttl. Um kid
+// desconhecido força nova busca (rotação de chaves), no máximo uma vez por minRefresh.
+func NewJWKSClient(url string, ttl, minRefresh time.Duration) JWKSClient {
+	return &jwksClient{
+		url:        url,
+		ttl:        ttl,
+		minRefresh: minRefresh,
+		httpClient: &http.Client{
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+			Timeout:   10 * time.Second,
+		},
+		tracer: otel.Tracer("service-a"),
+		now:    time.Now,
+	}
+}
+
+// Key consulta o cache sem esperar buscas em andamento; quando precisa buscar o
+// JWKS, requisições simultâneas aguardam a mesma busca, feita fora do lock
+func (c *jwksClient) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	age := c.now().Sub(c.fetchedAt)
+	key, ok := c.keys[kid]
+	if ok && age < c.ttl {
+		c.mu.Unlock()
+		return key, nil
+	}
+
+	// Busca novamente se o cache expirou ou se o kid é desconhecido
+	if c.keys != nil && age < c.ttl && age < c.minRefresh {
+		c.mu.Unlock()
+		return nil, ErrKeyNotFound
+	}
+	refresh := c.refreshing
+	if refresh == nil {
+		refresh = &jwksRefresh{done: make(chan struct{})}
+		c.refreshing = refresh
+		// A busca é compartilhada, então não depende do cancelamento de quem a iniciou
+		go c.refresh(context.WithoutCancel(ctx), refresh)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-refresh.done:
+	case <-ctx.Done():
+		if ok {
+			return key, nil
+		}
+		return nil, ctx.Err()
+	}
+
+	if refresh.err != nil {
+		// Mantém as chaves antigas se o provedor estiver indisponível
+		if ok {
+			return key, nil
+		}
+		return nil, refresh.err
+	}
+
+	c.mu.Lock()
+	key, ok = c.keys[kid]
+	c.mu.Unlock()
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+func (c *jwksClient) refresh(ctx context.Context, refresh *jwksRefresh) {
+	ctx, span := c.tracer.Start(ctx, "service-a.fetch-jwks")
+	keys, err := c.fetch(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+
+	c.mu.Lock()
+	if err == nil {
+		c.keys = keys
+		c.fetchedAt = c.now()
+	}
+	c.refreshing = nil
+	c.mu.Unlock()
+
+	refresh.err = err
+	close(refresh.done)
+}
+
+func (c *jwksClient) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching JWKS: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("error parsing JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			// Chaves de tipos não suportados não invalidam o restante do JWKS
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("invalid EC key: point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64url value: %w", err)
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// DiscoverJWKSURL obtém o jwks_uri do documento de descoberta OIDC do emissor
+func DiscoverJWKSURL(ctx context.Context, issuer string) (string, error) {
+	url := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+
+	client := &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport), Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error fetching OIDC discovery document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error fetching OIDC discovery document: status %d", resp.StatusCode)
+	}
+
+	var discovery struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+		return "", fmt.Errorf("error parsing OIDC discovery document: %w", err)
+	}
+	if discovery.JWKSURI == "" {
+		return "", errors.New("OIDC discovery document has no jwks_uri")
+	}
+
+	return discovery.JWKSURI, nil
+}
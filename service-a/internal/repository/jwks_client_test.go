@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func TestJWKSClientKey(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	keys := []map[string]string{
+		rsaJWK("rsa-1", &rsaKey.PublicKey),
+		{
+			"kty": "EC",
+			"kid": "ec-1",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(ecKey.X.Bytes()),
+			"y":   base64.RawURLEncoding.EncodeToString(ecKey.Y.Bytes()),
+		},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		{"kty": "oct", "kid": "hmac-1", "k": "c2VjcmV0"},
+	}
+
+	var (
+		fetches atomic.Int32
+		down    atomic.Bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	client := NewJWKSClient(server.URL, time.Hour, time.Minute).(*jwksClient)
+	client.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Chaves RSA e EC são aceitas e ficam em cache
+	key, err := client.Key(ctx, "rsa-1")
+	assert.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+
+	key, err = client.Key(ctx, "ec-1")
+	assert.NoError(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(key))
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// Chaves de cifragem e simétricas são ignoradas
+	for _, kid := range []string{"enc-1", "hmac-1"} {
+		_, err = client.Key(ctx, kid)
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	}
+	assert.Equal(t, int32(1), fetches.Load(), "unknown kid must not refetch before minRefresh")
+
+	// Após minRefresh um kid desconhecido força nova busca (rotação de chaves)
+	rotated, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keys = append(keys, rsaJWK("rsa-2", &rotated.PublicKey))
+	now = now.Add(2 * time.Minute)
+
+	key, err = client.Key(ctx, "rsa-2")
+	assert.NoError(t, err)
+	assert.True(t, rotated.PublicKey.Equal(key))
+	assert.Equal(t, int32(2), fetches.Load())
+
+	// Com o provedor fora do ar, chaves já conhecidas continuam válidas
+	down.Store(true)
+	now = now.Add(2 * time.Hour)
+
+	key, err = client.Key(ctx, "rsa-1")
+	assert.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+
+	_, err = client.Key(ctx, "rsa-3")
+	assert.Error(t, err)
+}
+
+func TestJWKSClientDoesNotBlockDuringRefresh(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A primeira busca responde na hora; as seguintes esperam release
+		if fetches.Add(1) > 1 {
+			<-release
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{rsaJWK("rsa-1", &rsaKey.PublicKey)}})
+	}))
+	defer server.Close()
+	defer close(release)
+
+	now := time.Now()
+	client := NewJWKSClient(server.URL, time.Hour, time.Minute).(*jwksClient)
+	client.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := client.Key(ctx, "rsa-1")
+	assert.NoError(t, err)
+
+	// Um kid desconhecido dispara uma busca lenta depois de minRefresh
+	now = now.Add(2 * time.Minute)
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := client.Key(ctx, "rsa-2")
+			results <- err
+		}()
+	}
+	assert.Eventually(t, func() bool { return fetches.Load() == 2 }, time.Second, time.Millisecond)
+
+	// Chaves em cache continuam respondendo enquanto a busca não termina
+	key, err := client.Key(ctx, "rsa-1")
+	assert.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+
+	// Quem desiste não espera a busca
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = client.Key(cancelled, "rsa-2")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	release <- struct{}{}
+	for i := 0; i < 2; i++ {
+		assert.ErrorIs(t, <-results, ErrKeyNotFound)
+	}
+	assert.Equal(t, int32(2), fetches.Load(), "concurrent lookups share one fetch")
+}
+
+func TestDiscoverJWKSURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		expectedURL string
+		expectErr   bool
+	}{
+		{
+			name:        "success",
+			status:      http.StatusOK,
+			body:        `{"issuer":"https://idp.example.com","jwks_uri":"https://idp.example.com/keys"}`,
+			expectedURL: "https://idp.example.com/keys",
+		},
+		{
+			name:      "missing jwks_uri",
+			status:    http.StatusOK,
+			body:      `{"issuer":"https://idp.example.com"}`,
+			expectErr: true,
+		},
+		{
+			name:      "provider error",
+			status:    http.StatusInternalServerError,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/.well-known/openid-configuration", r.URL.Path)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			url, err := DiscoverJWKSURL(context.Background(), server.URL+"/")
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedURL, url)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+)
+
+// QuotaUsage descreve o consumo de quota de um cliente na janela atual
+type QuotaUsage struct {
+	Limit     int64
+	Remaining int64
+	ResetAt   time.Time
+}
+
+// QuotaStore contabiliza as requisições de cada cliente por período
+type QuotaStore interface {
+	Consume(ctx context.Context, clientID string, limit int64) (QuotaUsage, error)
+}
+
+type quotaWindow struct {
+	used    int64
+	resetAt time.Time
+}
+
+type inMemoryQuotaStore struct {
+	period  time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	windows map[string]*quotaWindow
+}
+
+// NewInMemoryQuotaStore cria um store de quotas com janelas fixas de duração period
+func NewInMemoryQuotaStore(period time.Duration) QuotaStore {
+	return &inMemoryQuotaStore{
+		period:  period,
+		now:     time.Now,
+		windows: make(map[string]*quotaWindow),
+	}
+}
+
+func (s *inMemoryQuotaStore) Consume(ctx context.Context, clientID string, limit int64) (QuotaUsage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	window, ok := s.windows[clientID]
+	if !ok || !now.Before(window.resetAt) {
+		window = &quotaWindow{resetAt: now.Add(s.period)}
+		s.windows[clientID] = window
+	}
+
+	usage := QuotaUsage{Limit: limit, ResetAt: window.resetAt}
+	if window.used >= limit {
+		return usage, domain.ErrQuotaExceeded
+	}
+
+	window.used++
+	usage.Remaining = limit - window.used
+	return usage, nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryQuotaStoreConsume(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewInMemoryQuotaStore(time.Hour).(*inMemoryQuotaStore)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Consome toda a quota
+	usage, err := store.Consume(ctx, "ops", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, QuotaUsage{Limit: 2, Remaining: 1, ResetAt: now.Add(time.Hour)}, usage)
+
+	usage, err = store.Consume(ctx, "ops", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), usage.Remaining)
+
+	_, err = store.Consume(ctx, "ops", 2)
+	assert.Equal(t, domain.ErrQuotaExceeded, err)
+
+	// Outros clientes têm quota própria
+	_, err = store.Consume(ctx, "other", 2)
+	assert.NoError(t, err)
+
+	// A quota é renovada na próxima janela
+	now = now.Add(time.Hour)
+	usage, err = store.Consume(ctx, "ops", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), usage.Remaining)
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript aplica o token bucket atomicamente no Redis.
+// Retorna {permitido, fichas restantes} com as fichas como string para não perder a fração.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) / 1000 * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(capacity / rate * 1000))
+return {allowed, tostring(tokens)}
+`)
+
+type redisRateLimitStore struct {
+	client redis.Scripter
+	prefix string
+	now    func() time.Time
+}
+
+// NewRedisRateLimitStore cria um store compartilhado entre réplicas em um servidor
+// compatível com Redis. O relógio das réplicas deve estar sincronizado.
+func NewRedisRateLimitStore(client redis.Scripter, prefix string) RateLimitStore {
+	return &redisRateLimitStore{
+		client: client,
+		prefix: prefix,
+		now:    time.Now,
+	}
+}
+
+func (s *redisRateLimitStore) Take(ctx context.Context, key string, limit domain.RateLimit) (RateLimitResult, error) {
+	values, err := tokenBucketScript.Run(ctx, s.client,
+		[]string{s.prefix + key},
+		limit.TokensPerSecond(), limit.Capacity(), s.now().UnixMilli(),
+	).Slice()
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("error running rate limit script: %w", err)
+	}
+
+	if len(values) != 2 {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+	allowed, _ := values[0].(int64)
+	tokensStr, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("error parsing rate limit tokens: %w", err)
+	}
+
+	return newRateLimitResult(tokens, allowed == 1, limit), nil
+}
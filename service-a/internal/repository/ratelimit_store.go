@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+)
+
+// RateLimitResult descreve o estado do bucket após uma tentativa de consumo
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter é o tempo até o bucket voltar a ficar cheio
+	ResetAfter time.Duration
+	// RetryAfter é o tempo até a próxima ficha quando a requisição é negada
+	RetryAfter time.Duration
+}
+
+// RateLimitStore consome fichas de token buckets identificados por chave
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit domain.RateLimit) (RateLimitResult, error)
+}
+
+// refill repõe as fichas pelo tempo decorrido e tenta consumir uma
+func refill(tokens float64, elapsed time.Duration, limit domain.RateLimit) (float64, bool) {
+	capacity := float64(limit.Capacity())
+	tokens = math.Min(capacity, tokens+elapsed.Seconds()*limit.TokensPerSecond())
+	if tokens < 1 {
+		return tokens, false
+	}
+	return tokens - 1, true
+}
+
+func newRateLimitResult(tokens float64, allowed bool, limit domain.RateLimit) RateLimitResult {
+	rate := limit.TokensPerSecond()
+	capacity := float64(limit.Capacity())
+
+	result := RateLimitResult{
+		Allowed:    allowed,
+		Limit:      limit.Capacity(),
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: secondsToDuration((capacity - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+	return result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	limit     domain.RateLimit
+}
+
+type inMemoryRateLimitStore struct {
+	now       func() time.Time
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// sweepInterval controla a remoção de buckets cheios, que equivalem a buckets novos
+const sweepInterval = time.Minute
+
+// NewInMemoryRateLimitStore cria um store local, adequado a uma única réplica
+func NewInMemoryRateLimitStore() RateLimitStore {
+	return &inMemoryRateLimitStore{
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (s *inMemoryRateLimitStore) Take(ctx context.Context, key string, limit domain.RateLimit) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Capacity()), updatedAt: now}
+		s.buckets[key] = b
+	}
+
+	tokens, allowed := refill(b.tokens, now.Sub(b.updatedAt), limit)
+	b.tokens, b.updatedAt, b.limit = tokens, now, limit
+
+	return newRateLimitResult(tokens, allowed, limit), nil
+}
+
+// sweep remove buckets que já estariam cheios, limitando o uso de memória com muitas chaves
+func (s *inMemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if tokens, _ := refill(b.tokens, now.Sub(b.updatedAt), b.limit); tokens+1 >= float64(b.limit.Capacity()) {
+			delete(s.buckets, key)
+		}
+	}
+}
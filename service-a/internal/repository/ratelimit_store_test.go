@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitStoreTake(t *testing.T) {
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer redisClient.Close()
+
+	stores := map[string]func(now func() time.Time) RateLimitStore{
+		"in memory": func(now func() time.Time) RateLimitStore {
+			store := NewInMemoryRateLimitStore().(*inMemoryRateLimitStore)
+			store.now = now
+			return store
+		},
+		"redis": func(now func() time.Time) RateLimitStore {
+			server.FlushAll()
+			store := NewRedisRateLimitStore(redisClient, "test:").(*redisRateLimitStore)
+			store.now = now
+			return store
+		},
+	}
+
+	// 2 requisições por segundo com burst de 2
+	limit := domain.RateLimit{Requests: 2, Period: time.Second}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			store := newStore(func() time.Time { return now })
+			ctx := context.Background()
+
+			result, err := store.Take(ctx, "client:ops", limit)
+			assert.NoError(t, err)
+			assert.Equal(t, RateLimitResult{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: 500 * time.Millisecond}, result)
+
+			result, err = store.Take(ctx, "client:ops", limit)
+			assert.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, 0, result.Remaining)
+
+			// Bucket vazio: próxima ficha em 500ms
+			result, err = store.Take(ctx, "client:ops", limit)
+			assert.NoError(t, err)
+			assert.False(t, result.Allowed)
+			assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+			// Outras chaves têm bucket próprio
+			result, err = store.Take(ctx, "client:other", limit)
+			assert.NoError(t, err)
+			assert.True(t, result.Allowed)
+
+			// Reposição parcial
+			now = now.Add(500 * time.Millisecond)
+			result, err = store.Take(ctx, "client:ops", limit)
+			assert.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, 0, result.Remaining)
+		})
+	}
+}
+
+func TestInMemoryRateLimitStoreSweep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewInMemoryRateLimitStore().(*inMemoryRateLimitStore)
+	store.now = func() time.Time { return now }
+	limit := domain.RateLimit{Requests: 1, Period: time.Second}
+
+	store.Take(context.Background(), "ip:10.0.0.1", limit)
+	assert.Len(t, store.buckets, 1)
+
+	// Após o intervalo de limpeza, buckets cheios são descartados
+	now = now.Add(2 * sweepInterval)
+	store.Take(context.Background(), "ip:10.0.0.2", limit)
+	assert.Len(t, store.buckets, 1)
+	assert.Contains(t, store.buckets, "ip:10.0.0.2")
+}
+
+func TestRedisRateLimitStoreUnavailable(t *testing.T) {
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	defer redisClient.Close()
+	server.Close()
+
+	_, err := NewRedisRateLimitStore(redisClient, "test:").Take(context.Background(), "global", domain.RateLimit{Requests: 1, Period: time.Second})
+	assert.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/replay"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test ./internal/repository -run Replay -record regrava o golden file
+// contra o Service B de SERVICE_B_URL (padrão http://localhost:8081)
+var record = flag.Bool("record", false, "record golden files against a running Service B")
+
+func TestServiceBClientReplay(t *testing.T) {
+	path := filepath.Join("testdata", "replay", "service-b.json")
+	mode, baseURL := replay.ModeReplay, "http://replay.invalid"
+	if *record {
+		mode, baseURL = replay.ModeRecord, os.Getenv("SERVICE_B_URL")
+		if baseURL == "" {
+			baseURL = "http://localhost:8081"
+		}
+	}
+	recorder, err := replay.New(mode, path)
+	require.NoError(t, err)
+
+	client := NewServiceBClient(baseURL, WithTransportWrapper(recorder.Wrap))
+
+	tests := []struct {
+		cep            string
+		expectedCity   string
+		expectedStatus int
+	}{
+		{cep: "26140040", expectedCity: "Belford Roxo"},
+		{cep: "01310100", expectedCity: "São Paulo"},
+		{cep: "99999999", expectedStatus: http.StatusNotFound},
+		{cep: "1234567a", expectedStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cep, func(t *testing.T) {
+			weather, err := client.GetWeather(context.Background(), dto.WeatherRequest{CEP: tt.cep})
+			if tt.expectedStatus != 0 {
+				var serviceErr *domain.ServiceError
+				require.True(t, errors.As(err, &serviceErr))
+				assert.Equal(t, tt.expectedStatus, serviceErr.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCity, weather.City)
+			require.NotNil(t, weather.TempC)
+			require.NotNil(t, weather.TempK)
+			assert.InDelta(t, *weather.TempC+273, *weather.TempK, 0.11)
+		})
+	}
+	if !*record {
+		assert.Empty(t, recorder.Unused(), "golden file has interactions no test replays")
+	}
+}
@@ -3,19 +3,24 @@ package repository
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/deadline"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"golang.org/x/net/http2"
 )
 
+// ServiceBClient repassa a requisição (CEP e opções de conversão) ao Service B
 type ServiceBClient interface {
-	GetWeather(ctx context.Context, cep string) (*dto.WeatherResponse, error)
+	GetWeather(ctx context.Context, req dto.WeatherRequest) (*dto.WeatherResponse, error)
 }
 
 type serviceBClient struct {
@@ -23,20 +28,140 @@ type serviceBClient struct {
 	httpClient *http.Client
 }
 
-func NewServiceBClient(baseURL string) ServiceBClient {
+// Padrões do pool de conexões: todo o tráfego vai para o mesmo host, então o
+// limite de ociosas por host (2 no http.DefaultTransport) é o que importa
+const (
+	DefaultMaxIdleConnsPerHost = 100
+	DefaultIdleConnTimeout     = 90 * time.Second
+)
+
+// DefaultTimeout limita cada chamada ao Service B; o prazo da requisição
+// (repassado em X-Request-Timeout) pode encerrá-la antes
+const DefaultTimeout = 9 * time.Second
+
+// serviceBTransportConfig reúne o que as opções ajustam antes de montar o transport
+type serviceBTransportConfig struct {
+	timeout   time.Duration
+	transport *http.Transport
+	tls       bool
+	h2c       bool
+	wrappers  []func(http.RoundTripper) http.RoundTripper
+}
+
+// ServiceBClientOption customiza o cliente do Service B
+type ServiceBClientOption func(*serviceBTransportConfig)
+
+// WithTLSConfig habilita TLS (ou mTLS, se a configuração apresentar certificado) nas chamadas ao Service B
+func WithTLSConfig(config *tls.Config) ServiceBClientOption {
+	return func(c *serviceBTransportConfig) {
+		c.transport.TLSClientConfig = config
+		c.tls = true
+		// TLSClientConfig customizado desativa o HTTP/2 automático do transport
+		c.transport.ForceAttemptHTTP2 = true
+	}
+}
+
+// WithH2C usa HTTP/2 sem TLS (h2c, prior knowledge): as requisições são
+// multiplexadas em poucas conexões. Ignorado quando TLS está habilitado, pois
+// o HTTP/2 já é negociado via ALPN.
+func WithH2C() ServiceBClientOption {
+	return func(c *serviceBTransportConfig) {
+		c.h2c = true
+	}
+}
+
+// WithMaxIdleConnsPerHost define quantas conexões ociosas ficam no pool HTTP/1.1
+func WithMaxIdleConnsPerHost(n int) ServiceBClientOption {
+	return func(c *serviceBTransportConfig) {
+		c.transport.MaxIdleConnsPerHost = n
+		if c.transport.MaxIdleConns < n {
+			c.transport.MaxIdleConns = n
+		}
+	}
+}
+
+// WithMaxConnsPerHost limita as conexões simultâneas (0 = sem limite)
+func WithMaxConnsPerHost(n int) ServiceBClientOption {
+	return func(c *serviceBTransportConfig) {
+		c.transport.MaxConnsPerHost = n
+	}
+}
+
+// WithIdleConnTimeout define por quanto tempo uma conexão ociosa é mantida no pool HTTP/1.1
+func WithIdleConnTimeout(d time.Duration) ServiceBClientOption {
+	return func(c *serviceBTransportConfig) {
+		c.transport.IdleConnTimeout = d
+	}
+}
+
+// WithTimeout substitui DefaultTimeout
+func WithTimeout(d time.Duration) ServiceBClientOption {
+	return func(c *serviceBTransportConfig) {
+		c.timeout = d
+	}
+}
+
+// WithTransportWrapper envolve o transport antes da instrumentação (ex: replay.Recorder.Wrap)
+func WithTransportWrapper(wrap func(http.RoundTripper) http.RoundTripper) ServiceBClientOption {
+	return func(c *serviceBTransportConfig) {
+		c.wrappers = append(c.wrappers, wrap)
+	}
+}
+
+func NewServiceBClient(baseURL string, opts ...ServiceBClientOption) ServiceBClient {
+	config := newServiceBTransportConfig(opts...)
 	return &serviceBClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Transport: otelhttp.NewTransport(http.DefaultTransport),
-			Timeout:   30 * time.Second,
+			Transport: otelhttp.NewTransport(config.wrappedRoundTripper()),
+			Timeout:   config.timeout,
+		},
+	}
+}
+
+func newServiceBTransportConfig(opts ...ServiceBClientOption) *serviceBTransportConfig {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	transport.IdleConnTimeout = DefaultIdleConnTimeout
+
+	config := &serviceBTransportConfig{timeout: DefaultTimeout, transport: transport}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// wrappedRoundTripper aplica os wrappers de WithTransportWrapper sobre roundTripper
+func (c *serviceBTransportConfig) wrappedRoundTripper() http.RoundTripper {
+	transport := c.roundTripper()
+	for _, wrap := range c.wrappers {
+		transport = wrap(transport)
+	}
+	return transport
+}
+
+// roundTripper monta o transport HTTP/1.1 (ou HTTP/2 sobre TLS) ou o h2c
+func (c *serviceBTransportConfig) roundTripper() http.RoundTripper {
+	if !c.h2c || c.tls {
+		return c.transport
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	return &http2.Transport{
+		AllowHTTP: true,
+		// h2c usa a mesma interface de dial do TLS, mas com uma conexão TCP simples
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
 		},
+		// Pings detectam conexões mortas, já que uma única conexão carrega todo o tráfego
+		ReadIdleTimeout: 30 * time.Second,
+		PingTimeout:     15 * time.Second,
 	}
 }
 
-func (c *serviceBClient) GetWeather(ctx context.Context, cep string) (*dto.WeatherResponse, error) {
+func (c *serviceBClient) GetWeather(ctx context.Context, reqBody dto.WeatherRequest) (*dto.WeatherResponse, error) {
 
-	// Prepara a requisição
-	reqBody := dto.WeatherRequest{CEP: cep}
+	// Prepara a requisição; opções ausentes ficam fora do body
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("error marshaling request: %w", err)
@@ -50,6 +175,8 @@ func (c *serviceBClient) GetWeather(ctx context.Context, cep string) (*dto.Weath
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	// O Service B divide o tempo restante entre o ViaCEP e a WeatherAPI
+	deadline.Inject(ctx, req.Header)
 
 	// Faz a requisição
 	resp, err := c.httpClient.Do(req)
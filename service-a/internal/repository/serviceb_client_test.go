@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/compress"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/deadline"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestServiceBClientProtocols(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []ServiceBClientOption
+		expectedProto int
+	}{
+		{name: "http/1.1 by default", expectedProto: 1},
+		{name: "h2c", opts: []ServiceBClientOption{WithH2C()}, expectedProto: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Service B aceita HTTP/1.1 e h2c, como no servidor real
+			var (
+				mu     sync.Mutex
+				protos []int
+			)
+			serviceB := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				protos = append(protos, r.ProtoMajor)
+				mu.Unlock()
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"city":"Belford Roxo","temp_C":25.5,"temp_F":77.9,"temp_K":298.5}`))
+			}), &http2.Server{}))
+			defer serviceB.Close()
+
+			client := NewServiceBClient(serviceB.URL, tt.opts...)
+
+			// Requisições concorrentes: com h2c todas compartilham a conexão
+			var wg sync.WaitGroup
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					weather, err := client.GetWeather(context.Background(), dto.WeatherRequest{CEP: "26140040"})
+					assert.NoError(t, err)
+					assert.Equal(t, &dto.WeatherResponse{City: "Belford Roxo", TempC: proto.Float64(25.5), TempF: proto.Float64(77.9), TempK: proto.Float64(298.5)}, weather)
+				}()
+			}
+			wg.Wait()
+
+			require.Len(t, protos, 5)
+			for _, major := range protos {
+				assert.Equal(t, tt.expectedProto, major)
+			}
+		})
+	}
+}
+
+func TestServiceBClientDecompressesResponses(t *testing.T) {
+	// O transport pede gzip e descomprime de forma transparente
+	var acceptEncoding string
+	serviceB := httptest.NewServer(compress.New(compress.WithMinSize(1)).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"city":"Belford Roxo","temp_C":25.5,"temp_F":77.9,"temp_K":298.5}`))
+	})))
+	defer serviceB.Close()
+
+	weather, err := NewServiceBClient(serviceB.URL).GetWeather(context.Background(), dto.WeatherRequest{CEP: "26140040"})
+
+	require.NoError(t, err)
+	assert.Contains(t, acceptEncoding, "gzip")
+	assert.Equal(t, "Belford Roxo", weather.City)
+
+	// Confirma que a resposta realmente trafegou comprimida
+	req, _ := http.NewRequest("POST", serviceB.URL+"/weather", strings.NewReader(`{"cep":"26140040"}`))
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	_, err = gzip.NewReader(resp.Body)
+	assert.NoError(t, err)
+}
+
+func TestServiceBClientTransportOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []ServiceBClientOption
+		expectH2C bool
+		check     func(t *testing.T, transport *http.Transport)
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, transport *http.Transport) {
+				assert.Equal(t, DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+				assert.Equal(t, DefaultIdleConnTimeout, transport.IdleConnTimeout)
+				assert.Zero(t, transport.MaxConnsPerHost)
+			},
+		},
+		{
+			name: "pool tuning",
+			opts: []ServiceBClientOption{WithMaxIdleConnsPerHost(256), WithMaxConnsPerHost(64), WithIdleConnTimeout(time.Minute)},
+			check: func(t *testing.T, transport *http.Transport) {
+				assert.Equal(t, 256, transport.MaxIdleConnsPerHost)
+				assert.GreaterOrEqual(t, transport.MaxIdleConns, 256)
+				assert.Equal(t, 64, transport.MaxConnsPerHost)
+				assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+			},
+		},
+		{
+			name: "tls wins over h2c",
+			opts: []ServiceBClientOption{WithH2C(), WithTLSConfig(&tls.Config{})},
+			check: func(t *testing.T, transport *http.Transport) {
+				assert.NotNil(t, transport.TLSClientConfig)
+				assert.True(t, transport.ForceAttemptHTTP2)
+			},
+		},
+		{
+			name:      "h2c",
+			opts:      []ServiceBClientOption{WithH2C()},
+			expectH2C: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			switch rt := newServiceBTransportConfig(tt.opts...).roundTripper().(type) {
+			case *http2.Transport:
+				assert.True(t, tt.expectH2C)
+				assert.True(t, rt.AllowHTTP)
+			case *http.Transport:
+				assert.False(t, tt.expectH2C)
+				tt.check(t, rt)
+			default:
+				t.Fatalf("unexpected round tripper %T", rt)
+			}
+		})
+	}
+}
+
+func TestServiceBClientDeadline(t *testing.T) {
+	var header string
+	serviceB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(deadline.Header)
+		w.Write([]byte(`{"city":"Belford Roxo"}`))
+	}))
+	defer serviceB.Close()
+
+	// O tempo restante da requisição é repassado ao Service B
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err := NewServiceBClient(serviceB.URL).GetWeather(ctx, dto.WeatherRequest{CEP: "26140040"})
+	require.NoError(t, err)
+	budget, ok := deadline.Parse(header)
+	require.True(t, ok, header)
+	assert.InDelta(t, 2*time.Second, budget, float64(100*time.Millisecond))
+
+	// Sem prazo no ctx, o header não é enviado
+	_, err = NewServiceBClient(serviceB.URL).GetWeather(context.Background(), dto.WeatherRequest{CEP: "26140040"})
+	require.NoError(t, err)
+	assert.Empty(t, header)
+}
+
+func TestServiceBClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	serviceB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer serviceB.Close()
+	defer close(release)
+
+	// WithTimeout limita a chamada mesmo sem prazo no ctx
+	_, err := NewServiceBClient(serviceB.URL, WithTimeout(50*time.Millisecond)).GetWeather(context.Background(), dto.WeatherRequest{CEP: "26140040"})
+	var netErr net.Error
+	require.True(t, errors.As(err, &netErr), err)
+	assert.True(t, netErr.Timeout())
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	weatherv1 "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/api/weather/v1"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type serviceBGRPCClient struct {
+	client weatherv1.WeatherServiceClient
+}
+
+// DialServiceB cria a conexão gRPC com o Service B instrumentada com otelgrpc,
+// que propaga o contexto de trace (e o baggage) nos metadados. A conexão é
+// aberta na primeira chamada; erros aqui são apenas de target ou opções
+// inválidos. Sem tlsConfig a conexão não é cifrada; opts são somadas às padrão
+// (ex: interceptors de injeção de falhas).
+func DialServiceB(target string, tlsConfig *tls.Config, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}, opts...)
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating service B client: %w", err)
+	}
+	return conn, nil
+}
+
+// NewServiceBGRPCClient implementa ServiceBClient sobre gRPC
+func NewServiceBGRPCClient(conn grpc.ClientConnInterface) ServiceBClient {
+	return &serviceBGRPCClient{
+		client: weatherv1.NewWeatherServiceClient(conn),
+	}
+}
+
+func (c *serviceBGRPCClient) GetWeather(ctx context.Context, req dto.WeatherRequest) (*dto.WeatherResponse, error) {
+	grpcReq := &weatherv1.GetWeatherRequest{
+		Cep:              req.CEP,
+		Units:            req.Units,
+		ScientificKelvin: req.ScientificKelvin,
+	}
+	if req.Precision != nil {
+		precision := int32(*req.Precision)
+		grpcReq.Precision = &precision
+	}
+
+	resp, err := c.client.GetWeather(ctx, grpcReq)
+	if err != nil {
+		return nil, serviceErrorFromStatus(err)
+	}
+
+	// Escalas não pedidas chegam ausentes e continuam nil
+	return &dto.WeatherResponse{
+		City:  resp.GetCity(),
+		TempC: resp.TempC,
+		TempF: resp.TempF,
+		TempK: resp.TempK,
+		TempR: resp.TempR,
+	}, nil
+}
+
+// serviceErrorFromStatus converte o status gRPC no mesmo erro que o cliente HTTP
+// produziria, para que o handler responda igual nos dois transportes
+func serviceErrorFromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return fmt.Errorf("error calling service B: %w", err)
+	}
+
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return domain.NewServiceError(http.StatusUnprocessableEntity, st.Message())
+	case codes.NotFound:
+		return domain.NewServiceError(http.StatusNotFound, st.Message())
+	case codes.FailedPrecondition:
+		return domain.NewServiceError(http.StatusBadRequest, st.Message())
+	case codes.Internal:
+		return domain.NewServiceError(http.StatusInternalServerError, st.Message())
+	case codes.DeadlineExceeded:
+		return domain.NewServiceError(http.StatusGatewayTimeout, "deadline exceeded")
+	default:
+		// Falhas de transporte (ex: UNAVAILABLE)
+		return fmt.Errorf("error calling service B: %w", err)
+	}
+}
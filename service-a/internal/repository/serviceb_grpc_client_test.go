@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	weatherv1 "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/api/weather/v1"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel/oteltest"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/domain"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-a/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeWeatherServer faz o papel do Service B via gRPC
+type fakeWeatherServer struct {
+	weatherv1.UnimplementedWeatherServiceServer
+	resp     *weatherv1.GetWeatherResponse
+	err      error
+	received *weatherv1.GetWeatherRequest
+}
+
+func (s *fakeWeatherServer) GetWeather(ctx context.Context, req *weatherv1.GetWeatherRequest) (*weatherv1.GetWeatherResponse, error) {
+	s.received = req
+	return s.resp, s.err
+}
+
+func dialFakeServiceB(t *testing.T, fake *fakeWeatherServer) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	weatherv1.RegisterWeatherServiceServer(server, fake)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestServiceBGRPCClientGetWeather(t *testing.T) {
+	tests := []struct {
+		name          string
+		resp          *weatherv1.GetWeatherResponse
+		err           error
+		expected      *dto.WeatherResponse
+		expectedError *domain.ServiceError
+	}{
+		{
+			name: "success",
+			resp: &weatherv1.GetWeatherResponse{City: "Belford Roxo", TempC: proto.Float64(25.5), TempF: proto.Float64(77.9), TempK: proto.Float64(298.5)},
+			expected: &dto.WeatherResponse{
+				City:  "Belford Roxo",
+				TempC: proto.Float64(25.5),
+				TempF: proto.Float64(77.9),
+				TempK: proto.Float64(298.5),
+			},
+		},
+		{
+			name:          "invalid zipcode",
+			err:           status.Error(codes.InvalidArgument, "invalid zipcode"),
+			expectedError: domain.NewServiceError(http.StatusUnprocessableEntity, "invalid zipcode"),
+		},
+		{
+			name:          "zipcode not found",
+			err:           status.Error(codes.NotFound, "can not find zipcode"),
+			expectedError: domain.NewServiceError(http.StatusNotFound, "can not find zipcode"),
+		},
+		{
+			name:          "invalid location",
+			err:           status.Error(codes.FailedPrecondition, "invalid location"),
+			expectedError: domain.NewServiceError(http.StatusBadRequest, "invalid location"),
+		},
+		{
+			name:          "internal error",
+			err:           status.Error(codes.Internal, "internal server error"),
+			expectedError: domain.NewServiceError(http.StatusInternalServerError, "internal server error"),
+		},
+		{
+			name:          "deadline exceeded",
+			err:           status.Error(codes.DeadlineExceeded, "context deadline exceeded"),
+			expectedError: domain.NewServiceError(http.StatusGatewayTimeout, "deadline exceeded"),
+		},
+		{
+			name: "transport failure",
+			err:  status.Error(codes.Unavailable, "connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewServiceBGRPCClient(dialFakeServiceB(t, &fakeWeatherServer{resp: tt.resp, err: tt.err}))
+
+			result, err := client.GetWeather(context.Background(), dto.WeatherRequest{CEP: "26140040"})
+
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+				return
+			}
+
+			assert.Nil(t, result)
+			var serviceErr *domain.ServiceError
+			if tt.expectedError == nil {
+				assert.Error(t, err)
+				assert.False(t, errors.As(err, &serviceErr), "transport failures are not service errors")
+				return
+			}
+			require.True(t, errors.As(err, &serviceErr))
+			assert.Equal(t, tt.expectedError.StatusCode, serviceErr.StatusCode)
+			assert.Equal(t, tt.expectedError.Message, serviceErr.Message)
+		})
+	}
+}
+
+func TestServiceBGRPCClientForwardsConversionOptions(t *testing.T) {
+	fake := &fakeWeatherServer{resp: &weatherv1.GetWeatherResponse{City: "Belford Roxo", TempK: proto.Float64(298.65), TempR: proto.Float64(537.57)}}
+	client := NewServiceBGRPCClient(dialFakeServiceB(t, fake))
+
+	precision := 2
+	result, err := client.GetWeather(context.Background(), dto.WeatherRequest{CEP: "26140040", Units: []string{"R", "K"}, Precision: &precision, ScientificKelvin: true})
+
+	require.NoError(t, err)
+	expectedReq := &weatherv1.GetWeatherRequest{Cep: "26140040", Units: []string{"R", "K"}, Precision: proto.Int32(2), ScientificKelvin: true}
+	assert.True(t, proto.Equal(expectedReq, fake.received), "got %v", fake.received)
+	// Escalas não pedidas continuam nil
+	assert.Equal(t, &dto.WeatherResponse{City: "Belford Roxo", TempK: proto.Float64(298.65), TempR: proto.Float64(537.57)}, result)
+}
+
+func TestServiceBGRPCClientPropagatesTrace(t *testing.T) {
+	recorder := oteltest.Record(t)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	client := NewServiceBGRPCClient(dialFakeServiceB(t, &fakeWeatherServer{resp: &weatherv1.GetWeatherResponse{City: "Belford Roxo"}}))
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "service-a.call-service-b")
+	_, err := client.GetWeather(ctx, dto.WeatherRequest{CEP: "26140040"})
+	parent.End()
+	require.NoError(t, err)
+
+	spans := make(map[trace.SpanKind]sdktrace.ReadOnlySpan)
+	for _, s := range recorder.Ended() {
+		spans[s.SpanKind()] = s
+	}
+	clientSpan, serverSpan := spans[trace.SpanKindClient], spans[trace.SpanKindServer]
+	require.NotNil(t, clientSpan)
+	require.NotNil(t, serverSpan)
+
+	// O span do servidor é filho do span do cliente, no mesmo trace da requisição
+	assert.Equal(t, "weather.v1.WeatherService/GetWeather", clientSpan.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), clientSpan.Parent().SpanID())
+	assert.Equal(t, clientSpan.SpanContext().SpanID(), serverSpan.Parent().SpanID())
+	assert.Equal(t, parent.SpanContext().TraceID(), serverSpan.SpanContext().TraceID())
+}
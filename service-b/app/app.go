@@ -0,0 +1,199 @@
+// Package app monta o Service B a partir da configuração: é usado pelo main e
+// pelos testes end-to-end, que sobem os serviços no mesmo processo.
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/compress"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/config"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/deadline"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/decode"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/fault"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/health"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/mtls"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/openapi"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/replay"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/api"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/handler"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/repository"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/usecase"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// App reúne os handlers HTTP e gRPC do Service B
+type App struct {
+	// Handler é o roteador com compressão e h2c, pronto para o http.Server
+	Handler http.Handler
+	// TLSConfig é nil sem TLS_CERT_FILE
+	TLSConfig *tls.Config
+	// AdminHandler serve /admin/faults no listener de FAULT_ADMIN_ADDR; é nil
+	// sem FAULT_ADMIN_ENABLED
+	AdminHandler http.Handler
+
+	weatherUseCase usecase.WeatherUseCase
+}
+
+// New monta o Service B; os certificados são recarregados do disco até ctx terminar
+func New(ctx context.Context, config *Config) *App {
+	// Configura os clientes; as falhas injetadas ficam por fora da gravação,
+	// para nunca serem gravadas nos golden files
+	faults := setupFaults(config.Faults)
+	viacepClient := repository.NewViaCEPClient(config.Providers.ViaCEPBaseURL,
+		repository.WithTimeout(config.Providers.ViaCEPTimeout),
+		repository.WithTransportWrapper(setupRecorder(config.Replay, "viacep").Wrap),
+		repository.WithTransportWrapper(faults.Wrap("viacep")),
+	)
+	weatherClient := repository.NewWeatherClient(config.Providers.WeatherAPIBaseURL, config.Providers.WeatherAPIKey,
+		repository.WithTimeout(config.Providers.WeatherAPITimeout),
+		repository.WithTransportWrapper(setupRecorder(config.Replay, "weatherapi").Wrap),
+		repository.WithTransportWrapper(faults.Wrap("weatherapi")),
+	)
+	// O prazo da requisição (REQUEST_TIMEOUT ou o X-Request-Timeout do Service A)
+	// é dividido entre o ViaCEP e a WeatherAPI; no gRPC vale o deadline da chamada
+	weatherUseCase := usecase.NewWeatherUseCase(viacepClient, weatherClient,
+		usecase.WithZipcodeBudgetShare(config.Providers.ViaCEPBudgetShare),
+	)
+	middlewares := []func(http.Handler) http.Handler{deadline.Middleware(config.Server.RequestTimeout, config.Server.MaxRequestTimeout())}
+	if config.HTTP.OpenAPIValidateResponses {
+		middlewares = append(middlewares, setupValidator().Middleware)
+	}
+	weatherHandler := handler.NewWeatherHandler(weatherUseCase,
+		handler.WithMiddleware(middlewares...),
+		handler.WithDecoder(decode.New(
+			decode.WithMaxBytes(config.HTTP.MaxBodyBytes),
+			decode.WithUnknownFields(config.HTTP.JSONAllowUnknownFields),
+		)),
+	)
+
+	// Readiness verifica a alcançabilidade dos provedores e do coletor de traces
+	checker := health.NewChecker(config.Health.CacheTTL, config.Health.CheckTimeout)
+	checker.Register("viacep", health.HTTPCheck(http.DefaultClient, config.Providers.ViaCEPBaseURL))
+	checker.Register("weatherapi", health.HTTPCheck(http.DefaultClient, config.Providers.WeatherAPIBaseURL))
+	if endpoint := otel.ExporterEndpoint(); endpoint != "" {
+		checker.Register("trace-exporter", health.TCPCheck(endpoint))
+	}
+
+	router := weatherHandler.SetupRoutes()
+	router.Get("/healthz", health.LivenessHandler())
+	router.Get("/readyz", checker.ReadinessHandler())
+	router.Get("/openapi.json", openapi.SpecHandler(api.OpenAPISpec))
+	router.Get("/docs", openapi.SwaggerUIHandler("Service B", "/openapi.json"))
+
+	a := &App{
+		Handler:        serverHandler(config.HTTP, router),
+		weatherUseCase: weatherUseCase,
+	}
+	if config.Faults.AdminEnabled {
+		admin := http.NewServeMux()
+		faultsHandler := http.StripPrefix("/admin/faults", faults.Handler())
+		admin.Handle("/admin/faults", faultsHandler)
+		admin.Handle("/admin/faults/", faultsHandler)
+		a.AdminHandler = admin
+	}
+
+	// Com TLS_CA_FILE o certificado do cliente é obrigatório e, com
+	// TLS_ALLOWED_CLIENTS, sua identidade precisa estar na lista (ex: service-a)
+	if certs := loadCertificates(ctx, config.TLS); certs != nil {
+		a.TLSConfig = mtls.ServerConfig(certs, config.TLS.AllowedClients)
+	}
+	return a
+}
+
+// GRPCServer cria o servidor gRPC com as mesmas credenciais TLS do HTTP
+func (a *App) GRPCServer() *grpc.Server {
+	var opts []grpc.ServerOption
+	if a.TLSConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(a.TLSConfig)))
+	}
+	return handler.NewGRPCServer(handler.NewWeatherGRPCServer(a.weatherUseCase), opts...)
+}
+
+// setupValidator valida as respostas contra api/openapi.json
+// (OPENAPI_VALIDATE_RESPONSES, testes/desenvolvimento); o body das requisições
+// fica com o decoder do handler (pkg/decode)
+func setupValidator() *openapi.Validator {
+	validator, err := openapi.NewValidator(api.OpenAPISpec)
+	if err != nil {
+		log.Fatalf("Failed to load OpenAPI spec: %v", err)
+	}
+	return validator
+}
+
+// setupRecorder grava (HTTP_REPLAY_MODE=record) ou reproduz (replay) as trocas
+// com os provedores em HTTP_REPLAY_DIR/<name>.json, com a API key removida;
+// com "off" (padrão) não interfere
+func setupRecorder(config config.Replay, name string) *replay.Recorder {
+	mode, err := replay.ParseMode(string(config.Mode))
+	if err != nil {
+		log.Fatalf("Invalid HTTP_REPLAY_MODE: %v", err)
+	}
+
+	path := filepath.Join(config.Dir, name+".json")
+	recorder, err := replay.New(mode, path)
+	if err != nil {
+		log.Fatalf("Failed to set up HTTP replay: %v", err)
+	}
+	if mode != replay.ModeOff {
+		log.Printf("HTTP %s mode for %s (%s)", mode, name, path)
+	}
+	return recorder
+}
+
+// setupFaults lê as falhas a injetar por dependência (viacep e weatherapi) de
+// FAULTS, em JSON; com FAULT_ADMIN_ENABLED=true elas podem ser trocadas em
+// /admin/faults, no listener de FAULT_ADMIN_ADDR, sem reiniciar o serviço
+func setupFaults(config config.Faults) *fault.Injector {
+	rules, err := fault.ParseRules(config.Rules, faultDependencies...)
+	if err != nil {
+		log.Fatalf("Invalid FAULTS: %v", err)
+	}
+
+	injector := fault.New(fault.WithDependencies(faultDependencies...), fault.WithRules(rules))
+	if dependencies := injector.Dependencies(); len(dependencies) > 0 {
+		log.Printf("Warning: injecting faults into %s", strings.Join(dependencies, ", "))
+	}
+	return injector
+}
+
+// serverHandler adiciona a compressão das respostas (COMPRESSION_*) e o
+// suporte a HTTP/2 sem TLS (H2C_ENABLED), usado pelo Service A com SERVICE_B_H2C
+func serverHandler(config config.HTTP, router http.Handler) http.Handler {
+	handler := router
+	if config.CompressionEnabled {
+		handler = compress.New(
+			compress.WithMinSize(config.CompressionMinBytes),
+			compress.WithContentTypes(config.CompressionContentTypes...),
+		).Middleware(handler)
+	}
+	if config.H2CEnabled {
+		handler = h2c.NewHandler(handler, &http2.Server{})
+	}
+	return handler
+}
+
+// loadCertificates lê TLS_CERT_FILE, TLS_KEY_FILE e TLS_CA_FILE e os recarrega
+// quando mudam em disco; retorna nil se não houver certificado
+func loadCertificates(ctx context.Context, config config.TLS) *mtls.Reloader {
+	certFile := config.CertFile
+	if certFile == "" {
+		return nil
+	}
+
+	certs, err := mtls.NewReloader(certFile, config.KeyFile, config.CAFile)
+	if err != nil {
+		log.Fatalf("Failed to load TLS certificates: %v", err)
+	}
+	go certs.Watch(ctx, config.ReloadInterval)
+
+	return certs
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewServesFaultAdminOnlyOnAdminHandler(t *testing.T) {
+	v := NewConfig()
+	v.Set("weather_api_key", "test-key")
+	v.Set("fault_admin_enabled", true)
+	cfg, err := LoadConfig(v)
+	require.NoError(t, err)
+
+	a := New(context.Background(), cfg)
+	require.NotNil(t, a.AdminHandler)
+
+	// A porta pública não expõe o endpoint administrativo
+	rec := httptest.NewRecorder()
+	a.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/faults", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	a.AdminHandler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/faults", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	a.AdminHandler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/faults/viacep", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestLoadConfigRequiresLoopbackFaultAdmin(t *testing.T) {
+	v := NewConfig()
+	v.Set("weather_api_key", "test-key")
+	v.Set("fault_admin_enabled", true)
+	v.Set("fault_admin_addr", "0.0.0.0:9191")
+
+	_, err := LoadConfig(v)
+
+	var configErr *config.Error
+	require.True(t, errors.As(err, &configErr), err)
+	assert.Equal(t, []string{`FAULT_ADMIN_ADDR: must be a loopback address (localhost, 127.0.0.1 or ::1), got "0.0.0.0:9191"`}, configErr.Problems)
+}
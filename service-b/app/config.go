@@ -0,0 +1,140 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/compress"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/config"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/decode"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/replay"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/repository"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/usecase"
+	"github.com/spf13/viper"
+)
+
+// Config é a configuração tipada do Service B; as tags são os nomes das
+// variáveis de ambiente em minúsculas
+type Config struct {
+	Port int `mapstructure:"port"`
+	// GRPCEnabled sobe o servidor gRPC em GRPCPort (usado pelo Service A com
+	// SERVICE_B_TRANSPORT=grpc)
+	GRPCEnabled bool `mapstructure:"grpc_enabled"`
+	GRPCPort    int  `mapstructure:"grpc_port"`
+	// FaultAdminAddr é o listener de /admin/faults (FAULT_ADMIN_ENABLED): o
+	// Service B não tem autenticação, então ele fica fora da porta pública e
+	// restrito a loopback
+	FaultAdminAddr string `mapstructure:"fault_admin_addr"`
+
+	Providers ProvidersConfig `mapstructure:",squash"`
+
+	Server    config.Server    `mapstructure:",squash"`
+	TLS       config.TLS       `mapstructure:",squash"`
+	Replay    config.Replay    `mapstructure:",squash"`
+	Faults    config.Faults    `mapstructure:",squash"`
+	HTTP      config.HTTP      `mapstructure:",squash"`
+	Health    config.Health    `mapstructure:",squash"`
+	Telemetry config.Telemetry `mapstructure:",squash"`
+}
+
+// ProvidersConfig são as APIs externas consultadas (ViaCEP e WeatherAPI)
+type ProvidersConfig struct {
+	ViaCEPBaseURL     string `mapstructure:"viacep_base_url"`
+	WeatherAPIBaseURL string `mapstructure:"weather_api_base_url"`
+	WeatherAPIKey     string `mapstructure:"weather_api_key" secret:"true"`
+	// Timeouts de cada chamada, limitados também pelo prazo da requisição
+	ViaCEPTimeout     time.Duration `mapstructure:"viacep_timeout"`
+	WeatherAPITimeout time.Duration `mapstructure:"weather_api_timeout"`
+	// ViaCEPBudgetShare é a fração do prazo restante reservada ao ViaCEP
+	ViaCEPBudgetShare float64 `mapstructure:"viacep_budget_share"`
+}
+
+// faultDependencies são os clientes em que FAULTS injeta falhas
+var faultDependencies = []string{"viacep", "weatherapi"}
+
+// Validate reúne todos os problemas da configuração
+func (c *Config) Validate(v *config.Validator) {
+	v.Port("PORT", c.Port)
+	if c.GRPCEnabled {
+		v.Port("GRPC_PORT", c.GRPCPort)
+		if c.Port != 0 && c.Port == c.GRPCPort {
+			v.Addf("GRPC_PORT", "must differ from PORT (%d)", c.Port)
+		}
+	}
+	if c.Faults.AdminEnabled {
+		v.Loopback("FAULT_ADMIN_ADDR", c.FaultAdminAddr)
+	}
+	c.Providers.Validate(v)
+
+	c.Server.Validate(v)
+	c.TLS.Validate(v)
+	c.Replay.Validate(v)
+	c.Faults.Validate(v, faultDependencies...)
+	c.HTTP.Validate(v)
+	c.Health.Validate(v)
+	c.Telemetry.Validate(v)
+}
+
+func (c ProvidersConfig) Validate(v *config.Validator) {
+	v.Required("VIACEP_BASE_URL", c.ViaCEPBaseURL)
+	v.URL("VIACEP_BASE_URL", c.ViaCEPBaseURL)
+	v.Required("WEATHER_API_BASE_URL", c.WeatherAPIBaseURL)
+	v.URL("WEATHER_API_BASE_URL", c.WeatherAPIBaseURL)
+	v.Required("WEATHER_API_KEY", c.WeatherAPIKey)
+	v.Positive("VIACEP_TIMEOUT", c.ViaCEPTimeout)
+	v.Positive("WEATHER_API_TIMEOUT", c.WeatherAPITimeout)
+	v.Fraction("VIACEP_BUDGET_SHARE", c.ViaCEPBudgetShare)
+}
+
+// LoadConfig lê e valida a configuração; o erro (*config.Error) lista todos
+// os problemas encontrados
+func LoadConfig(v *viper.Viper) (*Config, error) {
+	cfg := &Config{}
+	return cfg, config.Load(v, cfg)
+}
+
+// NewConfig cria a configuração com os valores padrão, sobrescritos pelas
+// variáveis de ambiente
+func NewConfig() *viper.Viper {
+	v := viper.New()
+
+	v.SetDefault("port", 8081)
+	v.SetDefault("grpc_enabled", false)
+	v.SetDefault("grpc_port", 9091)
+	v.SetDefault("weather_api_key", "")
+	v.SetDefault("weather_api_base_url", "https://api.weatherapi.com/v1")
+	v.SetDefault("viacep_base_url", "https://viacep.com.br/ws")
+	v.SetDefault("viacep_timeout", repository.DefaultTimeout)
+	v.SetDefault("weather_api_timeout", repository.DefaultTimeout)
+	v.SetDefault("viacep_budget_share", usecase.DefaultZipcodeBudgetShare)
+	v.SetDefault("server_read_timeout", "10s")
+	v.SetDefault("server_write_timeout", "10s")
+	v.SetDefault("server_shutdown_timeout", "30s")
+	v.SetDefault("request_timeout", "9s")
+	v.SetDefault("http_replay_mode", string(replay.ModeOff))
+	v.SetDefault("http_replay_dir", "testdata/replay")
+	v.SetDefault("faults", "")
+	v.SetDefault("fault_admin_enabled", false)
+	v.SetDefault("fault_admin_addr", "127.0.0.1:9191")
+	v.SetDefault("tls_cert_file", "")
+	v.SetDefault("tls_key_file", "")
+	v.SetDefault("tls_ca_file", "")
+	v.SetDefault("tls_allowed_clients", "")
+	v.SetDefault("tls_reload_interval", "30s")
+	v.SetDefault("openapi_validate_responses", false)
+	v.SetDefault("max_body_bytes", decode.DefaultMaxBytes)
+	v.SetDefault("json_allow_unknown_fields", false)
+	v.SetDefault("compression_enabled", true)
+	v.SetDefault("compression_min_bytes", compress.DefaultMinSize)
+	v.SetDefault("compression_content_types", strings.Join(compress.DefaultContentTypes, ","))
+	v.SetDefault("h2c_enabled", true)
+	v.SetDefault("health_cache_ttl", "10s")
+	v.SetDefault("health_check_timeout", "2s")
+	v.SetDefault("zipkin_url", "")
+	v.SetDefault("otel_propagators", "")
+	v.SetDefault("deployment_environment", "")
+
+	v.AutomaticEnv()
+
+	return v
+}
@@ -2,53 +2,118 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/config"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel"
-	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/handler"
-	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/repository"
-	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/usecase"
-	"github.com/spf13/viper"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/app"
+	"google.golang.org/grpc"
+)
+
+// Injetados em tempo de build via -ldflags "-X main.version=... -X main.commit=..."
+var (
+	version = "dev"
+	commit  = "unknown"
 )
 
 func main() {
-	config := setupConfig()
+	printConfig := flag.Bool("print-config", false, "print the effective configuration (secrets masked) and exit")
+	flag.Parse()
+
+	cfg, err := setupConfig()
+	if *printConfig {
+		// Imprime o que foi possível ler mesmo com erros, que vão para o stderr
+		if cfg != nil {
+			config.Print(os.Stdout, cfg)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	// Inicializa o OpenTelemetry
-	shutdown, err := otel.InitTracer("service-b", config.GetString("zipkin_url"))
+	// Inicializa o OpenTelemetry (variáveis OTEL_* padrão, sobrescritas pelo .env)
+	shutdown, err := otel.InitTracer("service-b",
+		otel.WithZipkinEndpoint(cfg.Telemetry.ZipkinURL),
+		otel.WithPropagators(cfg.Telemetry.Propagators...),
+		otel.WithVersion(version, commit),
+		otel.WithEnvironment(cfg.Telemetry.Environment),
+	)
 	if err != nil {
 		log.Fatalf("Failed to initialize tracer: %v", err)
 	}
 	defer shutdown()
 
-	// Configura os clientes
-	viacepClient := repository.NewViaCEPClient(config.GetString("viacep_base_url"))
-	weatherClient := repository.NewWeatherClient(config.GetString("weather_api_base_url"), config.GetString("weather_api_key"))
-	weatherUseCase := usecase.NewWeatherUseCase(viacepClient, weatherClient)
-	weatherHandler := handler.NewWeatherHandler(weatherUseCase)
+	// Certificados são recarregados do disco até o serviço parar
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+
+	serviceB := app.New(watchCtx, cfg)
 
 	// Configura o servidor
 	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", config.GetInt("port")),
-		Handler:      weatherHandler.SetupRoutes(),
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		Addr:         fmt.Sprintf(":%d", cfg.Port),
+		Handler:      serviceB.Handler,
+		TLSConfig:    serviceB.TLSConfig,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
 	}
 
 	// Inicia o servidor
 	go func() {
-		log.Printf("Service B running on port %d", config.GetInt("port"))
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("Service B %s (%s) running on port %d (tls: %t)", version, commit, cfg.Port, server.TLSConfig != nil)
+		if err := listen(server); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Error starting server: %v", err)
 		}
 	}()
 
+	// Servidor gRPC (GRPC_ENABLED, na GRPC_PORT), com as mesmas credenciais TLS do HTTP
+	var grpcServer *grpc.Server
+	if cfg.GRPCEnabled {
+		grpcServer = serviceB.GRPCServer()
+
+		go func() {
+			listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
+			if err != nil {
+				log.Fatalf("Error starting gRPC listener: %v", err)
+			}
+			log.Printf("Service B gRPC running on port %d", cfg.GRPCPort)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Error starting gRPC server: %v", err)
+			}
+		}()
+	}
+
+	// Endpoint administrativo (FAULT_ADMIN_ENABLED), apenas em loopback (FAULT_ADMIN_ADDR)
+	var adminServer *http.Server
+	if serviceB.AdminHandler != nil {
+		adminServer = &http.Server{
+			Addr:         cfg.FaultAdminAddr,
+			Handler:      serviceB.AdminHandler,
+			ReadTimeout:  cfg.Server.ReadTimeout,
+			WriteTimeout: cfg.Server.WriteTimeout,
+		}
+
+		go func() {
+			log.Printf("Service B admin running on %s", cfg.FaultAdminAddr)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Error starting admin server: %v", err)
+			}
+		}()
+	}
+
 	// Aguarda o sinal de parada
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -57,39 +122,60 @@ func main() {
 	log.Println("Stopping Service B...")
 
 	// Fecha o servidor
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
-	// Encerra o servidor
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Error stopping server: %v", err)
+	// Encerra os servidores; o gRPC drena as chamadas em paralelo ao HTTP e,
+	// se o prazo acabar antes, tem as conexões fechadas à força
+	grpcStopped := make(chan struct{})
+	if grpcServer != nil {
+		go func() {
+			grpcServer.GracefulStop()
+			close(grpcStopped)
+		}()
+	}
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			log.Printf("Error stopping admin server: %v", err)
+		}
+	}
+	shutdownErr := server.Shutdown(ctx)
+	if grpcServer != nil {
+		select {
+		case <-grpcStopped:
+		case <-ctx.Done():
+			log.Printf("Error stopping gRPC server: %v", ctx.Err())
+			grpcServer.Stop()
+			<-grpcStopped
+		}
+	}
+	if shutdownErr != nil {
+		log.Fatalf("Error stopping server: %v", shutdownErr)
 	}
 
 	log.Println("Service B stopped successfully")
 }
 
-func setupConfig() *viper.Viper {
-	v := viper.New()
-
-	v.SetDefault("port", 8081)
-	v.SetDefault("weather_api_key", "")
-	v.SetDefault("weather_api_base_url", "https://api.weatherapi.com/v1")
-	v.SetDefault("viacep_base_url", "https://viacep.com.br/ws")
-	v.SetDefault("zipkin_url", "http://localhost:9411/api/v2/spans")
+func listen(server *http.Server) error {
+	if server.TLSConfig != nil {
+		return server.ListenAndServeTLS("", "")
+	}
+	return server.ListenAndServe()
+}
 
-	v.AutomaticEnv()
+// setupConfig lê o .env (opcional) e as variáveis de ambiente e valida o
+// resultado; um .env inválido é erro, não apenas aviso
+func setupConfig() (*app.Config, error) {
+	v := app.NewConfig()
 
 	v.SetConfigFile(".env")
-	if err := v.ReadInConfig(); err != nil {
-		log.Printf("Warning: .env file not found: %v", err)
-		log.Println("Using default values and environment variables")
+	if err := v.ReadInConfig(); errors.Is(err, fs.ErrNotExist) {
+		log.Println(".env file not found, using default values and environment variables")
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading .env: %w", err)
 	} else {
 		log.Println(".env file loaded")
 	}
 
-	if v.GetString("weather_api_key") == "" {
-		log.Fatal("WEATHER_API_KEY is required. Configure in .env or as environment variable")
-	}
-
-	return v
+	return app.LoadConfig(v)
 }
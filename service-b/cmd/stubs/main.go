@@ -0,0 +1,92 @@
+// Stubs emula o ViaCEP e a WeatherAPI a partir de fixtures para rodar a stack
+// sem internet: VIACEP_BASE_URL=http://<stubs>/ws e WEATHER_API_BASE_URL=http://<stubs>/v1
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/stubs"
+	"github.com/spf13/viper"
+)
+
+func main() {
+	config := setupConfig()
+
+	fixtures, err := stubs.LoadFixturesFile(config.GetString("fixtures_file"))
+	if err != nil {
+		log.Fatalf("Failed to load fixtures: %v", err)
+	}
+	unknownZipcode, err := stubs.ParseUnknownZipcode(config.GetString("unknown_cep"))
+	if err != nil {
+		log.Fatalf("Invalid STUBS_UNKNOWN_CEP: %v", err)
+	}
+
+	stubServer := stubs.New(fixtures,
+		stubs.WithUnknownZipcode(unknownZipcode),
+		stubs.WithAPIKey(config.GetString("api_key")),
+		stubs.WithViaCEPFaults(faults(config, "viacep")),
+		stubs.WithWeatherFaults(faults(config, "weather")),
+	)
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", config.GetInt("port")),
+		Handler:           stubServer.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Printf("Stubs running on port %d (%d zipcodes, unknown cep: %s)", config.GetInt("port"), len(fixtures.Zipcodes), unknownZipcode)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Error starting server: %v", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Fatalf("Error stopping server: %v", err)
+	}
+	log.Println("Stubs stopped")
+}
+
+// faults lê STUBS_<UPSTREAM>_LATENCY, _JITTER, _ERROR_RATE e _ERROR_STATUS
+func faults(config *viper.Viper, upstream string) stubs.Faults {
+	return stubs.Faults{
+		Latency:     config.GetDuration(upstream + "_latency"),
+		Jitter:      config.GetDuration(upstream + "_jitter"),
+		ErrorRate:   config.GetFloat64(upstream + "_error_rate"),
+		ErrorStatus: config.GetInt(upstream + "_error_status"),
+	}
+}
+
+// setupConfig lê apenas variáveis STUBS_*, para não colidir com as do Service B
+func setupConfig() *viper.Viper {
+	v := viper.New()
+
+	v.SetDefault("port", 8090)
+	v.SetDefault("fixtures_file", "")
+	v.SetDefault("unknown_cep", string(stubs.UnknownNotFound))
+	v.SetDefault("api_key", "")
+	for _, upstream := range []string{"viacep", "weather"} {
+		v.SetDefault(upstream+"_latency", "0s")
+		v.SetDefault(upstream+"_jitter", "0s")
+		v.SetDefault(upstream+"_error_rate", 0)
+		v.SetDefault(upstream+"_error_status", http.StatusInternalServerError)
+	}
+
+	v.SetEnvPrefix("stubs")
+	v.AutomaticEnv()
+
+	return v
+}
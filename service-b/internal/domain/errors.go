@@ -7,4 +7,7 @@ var (
 	ErrZipcodeNotFound = errors.New("can not find zipcode")
 	ErrWeatherNotFound = errors.New("weather not found")
 	ErrInvalidLocation = errors.New("invalid location")
+	// ErrDeadlineExceeded indica que o orçamento da requisição (ou o timeout
+	// de um provedor) acabou antes da resposta
+	ErrDeadlineExceeded = errors.New("deadline exceeded")
 )
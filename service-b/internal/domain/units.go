@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// Unit é uma escala de temperatura da resposta
+type Unit string
+
+const (
+	Celsius    Unit = "C"
+	Fahrenheit Unit = "F"
+	Kelvin     Unit = "K"
+	Rankine    Unit = "R"
+)
+
+// DefaultUnits são as escalas do contrato original do laboratório
+var DefaultUnits = []Unit{Celsius, Fahrenheit, Kelvin}
+
+// DefaultPrecision é o número de casas decimais do contrato original
+const DefaultPrecision = 1
+
+// MaxPrecision limita as casas decimais ao que a leitura do provedor suporta
+const MaxPrecision = 6
+
+// Options define escalas, arredondamento e a fórmula de Kelvin da resposta
+type Options struct {
+	// Units são as escalas retornadas, na ordem informada (vazio = DefaultUnits)
+	Units []Unit
+	// Precision é o número de casas decimais (0 a MaxPrecision)
+	Precision int
+	// ScientificKelvin usa K = C + 273.15 em vez do K = C + 273 do contrato
+	ScientificKelvin bool
+}
+
+// DefaultOptions reproduz a resposta original: C, F e K com uma casa decimal
+func DefaultOptions() Options {
+	return Options{Precision: DefaultPrecision}
+}
+
+// NewWeatherWithOptions converte a temperatura em Celsius para as escalas
+// pedidas; as escalas não pedidas ficam zeradas e fora do JSON
+func NewWeatherWithOptions(city string, tempCelsius float64, opts Options) Weather {
+	weather := Weather{City: city, Units: opts.Units}
+	for _, unit := range weather.RequestedUnits() {
+		switch unit {
+		case Celsius:
+			weather.TempC = roundTo(tempCelsius, opts.Precision)
+		case Fahrenheit:
+			weather.TempF = roundTo(celsiusToFahrenheit(tempCelsius), opts.Precision)
+		case Kelvin:
+			kelvin := celsiusToKelvin(tempCelsius)
+			if opts.ScientificKelvin {
+				kelvin = celsiusToKelvinScientific(tempCelsius)
+			}
+			weather.TempK = roundTo(kelvin, opts.Precision)
+		case Rankine:
+			weather.TempR = roundTo(celsiusToRankine(tempCelsius), opts.Precision)
+		}
+	}
+	return weather
+}
+
+// MarshalJSON escreve a cidade e apenas as escalas pedidas, na ordem pedida
+func (w Weather) MarshalJSON() ([]byte, error) {
+	city, err := json.Marshal(w.City)
+	if err != nil {
+		return nil, err
+	}
+
+	out := append([]byte(`{"city":`), city...)
+	for _, unit := range w.RequestedUnits() {
+		value, err := json.Marshal(w.Temperature(unit))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, `,"temp_`+string(unit)+`":`...)
+		out = append(out, value...)
+	}
+	return append(out, '}'), nil
+}
+
+// Temperature retorna a temperatura na escala informada
+func (w Weather) Temperature(unit Unit) float64 {
+	switch unit {
+	case Celsius:
+		return w.TempC
+	case Fahrenheit:
+		return w.TempF
+	case Kelvin:
+		return w.TempK
+	case Rankine:
+		return w.TempR
+	default:
+		return 0
+	}
+}
+
+// RequestedUnits retorna as escalas pedidas, ou DefaultUnits se nenhuma foi pedida
+func (w Weather) RequestedUnits() []Unit {
+	if len(w.Units) == 0 {
+		return DefaultUnits
+	}
+	return w.Units
+}
+
+// Fórmula: K = C + 273.15
+func celsiusToKelvinScientific(celsius float64) float64 {
+	return celsius + 273.15
+}
+
+// Fórmula: R = (C + 273.15) * 1.8, sempre a partir do zero absoluto exato
+func celsiusToRankine(celsius float64) float64 {
+	return celsiusToKelvinScientific(celsius) * 1.8
+}
+
+func roundTo(value float64, precision int) float64 {
+	factor := math.Pow(10, float64(precision))
+	return math.Round(value*factor) / factor
+}
@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// temperature gera leituras entre o zero absoluto e 1000 °C para os testes de propriedade
+type temperature float64
+
+func (temperature) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(temperature(-273.15 + r.Float64()*1273.15))
+}
+
+// precision gera casas decimais válidas (0 a MaxPrecision)
+type precision int
+
+func (precision) Generate(r *rand.Rand, _ int) reflect.Value {
+	return reflect.ValueOf(precision(r.Intn(MaxPrecision + 1)))
+}
+
+const epsilon = 1e-9
+
+func TestConversionProperties(t *testing.T) {
+	properties := []struct {
+		name     string
+		property interface{}
+	}{
+		{
+			name: "fahrenheit is linear in celsius",
+			property: func(c temperature) bool {
+				return math.Abs(celsiusToFahrenheit(float64(c))-(float64(c)*9/5+32)) < epsilon
+			},
+		},
+		{
+			name: "kelvin keeps the contract offset",
+			property: func(c temperature) bool {
+				return math.Abs(celsiusToKelvin(float64(c))-float64(c)-273) < epsilon
+			},
+		},
+		{
+			name: "scientific kelvin is never negative and uses 273.15",
+			property: func(c temperature) bool {
+				k := celsiusToKelvinScientific(float64(c))
+				return k >= -epsilon && math.Abs(k-float64(c)-273.15) < epsilon
+			},
+		},
+		{
+			name: "rankine is scientific kelvin times 1.8",
+			property: func(c temperature) bool {
+				return math.Abs(celsiusToRankine(float64(c))-celsiusToKelvinScientific(float64(c))*1.8) < epsilon
+			},
+		},
+		{
+			name: "rankine is fahrenheit plus 459.67",
+			property: func(c temperature) bool {
+				return math.Abs(celsiusToRankine(float64(c))-(celsiusToFahrenheit(float64(c))+459.67)) < 1e-6
+			},
+		},
+		{
+			name: "conversions preserve order",
+			property: func(a, b temperature) bool {
+				if a > b {
+					a, b = b, a
+				}
+				lo, hi := float64(a), float64(b)
+				return celsiusToFahrenheit(lo) <= celsiusToFahrenheit(hi) &&
+					celsiusToKelvin(lo) <= celsiusToKelvin(hi) &&
+					celsiusToKelvinScientific(lo) <= celsiusToKelvinScientific(hi) &&
+					celsiusToRankine(lo) <= celsiusToRankine(hi)
+			},
+		},
+		{
+			name: "rounding error is at most half a unit of precision",
+			property: func(c temperature, p precision) bool {
+				return math.Abs(roundTo(float64(c), int(p))-float64(c)) <= 0.5*math.Pow(10, -float64(p))+epsilon
+			},
+		},
+		{
+			name: "rounding is idempotent",
+			property: func(c temperature, p precision) bool {
+				rounded := roundTo(float64(c), int(p))
+				return roundTo(rounded, int(p)) == rounded
+			},
+		},
+	}
+
+	for _, tt := range properties {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, quick.Check(tt.property, &quick.Config{MaxCount: 1000}))
+		})
+	}
+}
+
+func TestNewWeatherWithOptionsProperties(t *testing.T) {
+	// Cada escala pedida é a conversão arredondada; as demais ficam zeradas
+	property := func(c temperature, p precision, scientific bool, mask uint8) bool {
+		var units []Unit
+		for i, unit := range []Unit{Celsius, Fahrenheit, Kelvin, Rankine} {
+			if mask&(1<<i) != 0 {
+				units = append(units, unit)
+			}
+		}
+		opts := Options{Units: units, Precision: int(p), ScientificKelvin: scientific}
+		weather := NewWeatherWithOptions("Belford Roxo", float64(c), opts)
+
+		kelvin := celsiusToKelvin(float64(c))
+		if scientific {
+			kelvin = celsiusToKelvinScientific(float64(c))
+		}
+		expected := map[Unit]float64{
+			Celsius:    roundTo(float64(c), int(p)),
+			Fahrenheit: roundTo(celsiusToFahrenheit(float64(c)), int(p)),
+			Kelvin:     roundTo(kelvin, int(p)),
+			Rankine:    roundTo(celsiusToRankine(float64(c)), int(p)),
+		}
+
+		requested := make(map[Unit]bool)
+		for _, unit := range weather.RequestedUnits() {
+			requested[unit] = true
+		}
+		for unit, value := range expected {
+			if requested[unit] && weather.Temperature(unit) != value {
+				return false
+			}
+			if !requested[unit] && weather.Temperature(unit) != 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	assert.NoError(t, quick.Check(property, &quick.Config{MaxCount: 1000}))
+}
+
+func TestNewWeatherWithOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		tempCelsius  float64
+		opts         Options
+		expectedJSON string
+	}{
+		{
+			name:         "default options keep the original contract",
+			tempCelsius:  28.46,
+			opts:         DefaultOptions(),
+			expectedJSON: `{"city":"Belford Roxo","temp_C":28.5,"temp_F":83.2,"temp_K":301.5}`,
+		},
+		{
+			name:         "unit subset in requested order",
+			tempCelsius:  28.46,
+			opts:         Options{Units: []Unit{Kelvin, Celsius}, Precision: 1},
+			expectedJSON: `{"city":"Belford Roxo","temp_K":301.5,"temp_C":28.5}`,
+		},
+		{
+			name:         "rankine",
+			tempCelsius:  0,
+			opts:         Options{Units: []Unit{Rankine}, Precision: 2},
+			expectedJSON: `{"city":"Belford Roxo","temp_R":491.67}`,
+		},
+		{
+			name:         "scientific kelvin with precision",
+			tempCelsius:  28.46,
+			opts:         Options{Units: []Unit{Kelvin}, Precision: 2, ScientificKelvin: true},
+			expectedJSON: `{"city":"Belford Roxo","temp_K":301.61}`,
+		},
+		{
+			name:         "zero precision",
+			tempCelsius:  28.46,
+			opts:         Options{Units: []Unit{Celsius, Fahrenheit}, Precision: 0},
+			expectedJSON: `{"city":"Belford Roxo","temp_C":28,"temp_F":83}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(NewWeatherWithOptions("Belford Roxo", tt.tempCelsius, tt.opts))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedJSON, string(body))
+
+			// A ordem das escalas também faz parte da resposta
+			assert.Equal(t, tt.expectedJSON, string(body))
+		})
+	}
+}
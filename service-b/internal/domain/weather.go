@@ -2,14 +2,17 @@ package domain
 
 import (
 	"fmt"
-	"math"
 )
 
+// Weather é serializado com MarshalJSON (units.go), que omite as escalas não pedidas
 type Weather struct {
 	City  string  `json:"city"`
 	TempC float64 `json:"temp_C"`
 	TempF float64 `json:"temp_F"`
 	TempK float64 `json:"temp_K"`
+	TempR float64 `json:"temp_R"`
+	// Units são as escalas pedidas (vazio = C, F e K)
+	Units []Unit `json:"-"`
 }
 
 type Location struct {
@@ -17,14 +20,10 @@ type Location struct {
 	State string `json:"state"`
 }
 
-// NewWeather cria uma nova instância de Weather a partir da temperatura em Celsius e cidade
+// NewWeather cria uma nova instância de Weather a partir da temperatura em Celsius e cidade,
+// com as escalas e o arredondamento padrão (DefaultOptions)
 func NewWeather(city string, tempCelsius float64) Weather {
-	return Weather{
-		City:  city,
-		TempC: roundToOneDecimal(tempCelsius),
-		TempF: roundToOneDecimal(celsiusToFahrenheit(tempCelsius)),
-		TempK: roundToOneDecimal(celsiusToKelvin(tempCelsius)),
-	}
+	return NewWeatherWithOptions(city, tempCelsius, DefaultOptions())
 }
 
 // Fórmula: F = C * 1.8 + 32
@@ -37,10 +36,6 @@ func celsiusToKelvin(celsius float64) float64 {
 	return celsius + 273
 }
 
-func roundToOneDecimal(value float64) float64 {
-	return math.Round(value*10) / 10
-}
-
 func ValidateZipcode(zipcode string) error {
 	if len(zipcode) != 8 {
 		return ErrInvalidZipcode
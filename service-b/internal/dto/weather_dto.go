@@ -1,8 +1,16 @@
 package dto
 
+import "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/decode"
+
 // WeatherRequest representa a requisição do Serviço A
 type WeatherRequest struct {
 	CEP string `json:"cep" validate:"required,len=8"`
+	// Units são as escalas da resposta (C, F, K e R), na ordem desejada; vazio = C, F e K
+	Units []string `json:"units,omitempty" validate:"omitempty,max=4,unique,dive,oneof=C F K R"`
+	// Precision é o número de casas decimais; ausente = 1
+	Precision *int `json:"precision,omitempty" validate:"omitempty,min=0,max=6"`
+	// ScientificKelvin usa K = C + 273.15 em vez de C + 273
+	ScientificKelvin bool `json:"scientific_kelvin,omitempty"`
 }
 
 type ViaCEPResponse struct {
@@ -33,4 +41,6 @@ type WeatherAPIResponse struct {
 
 type ErrorResponse struct {
 	Message string `json:"message"`
+	// Errors detalha os campos inválidos do body, quando houver
+	Errors []decode.FieldError `json:"errors,omitempty"`
 }
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/openapi"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/api"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWeatherHandlerMatchesOpenAPISpec garante que as respostas do handler
+// seguem api/openapi.json; uma divergência vira 500 no modo de teste. O
+// validador é o de app.setupValidator: o body fica com o decoder.
+func TestWeatherHandlerMatchesOpenAPISpec(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		mockWeather    *domain.Weather
+		mockErr        error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "success",
+			contentType:    "application/json",
+			body:           `{"cep":"26140040"}`,
+			mockWeather:    &domain.Weather{City: "Belford Roxo", TempC: 25.5, TempF: 77.9, TempK: 298.5},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid zipcode",
+			contentType:    "application/json",
+			body:           `{"cep":"1234567a"}`,
+			mockErr:        domain.ErrInvalidZipcode,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "zipcode not found",
+			contentType:    "application/json",
+			body:           `{"cep":"99999999"}`,
+			mockErr:        domain.ErrZipcodeNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid location",
+			contentType:    "application/json",
+			body:           `{"cep":"26140040"}`,
+			mockErr:        domain.ErrInvalidLocation,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unexpected error",
+			contentType:    "application/json",
+			body:           `{"cep":"26140040"}`,
+			mockErr:        errors.New("connection reset"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "success with unit subset",
+			contentType:    "application/json",
+			body:           `{"cep":"26140040","units":["R"],"precision":2}`,
+			mockWeather:    &domain.Weather{City: "Belford Roxo", TempR: 537.57, Units: []domain.Unit{domain.Rankine}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown unit",
+			contentType:    "application/json",
+			body:           `{"cep":"26140040","units":["X"]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"message":"validation failed","errors":[{"field":"units[0]","message":"must be one of: C F K R"}]}`,
+		},
+		{
+			name:           "missing required cep",
+			contentType:    "application/json",
+			body:           `{}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"message":"invalid zipcode","errors":[{"field":"cep","message":"is required"}]}`,
+		},
+		{
+			name:           "cep is not a string",
+			contentType:    "application/json",
+			body:           `{"cep":26140040}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"message":"invalid request body","errors":[{"field":"cep","message":"must be a string"}]}`,
+		},
+		{
+			name:           "malformed json",
+			contentType:    "application/json",
+			body:           `{"cep":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"message":"invalid request body"}`,
+		},
+		{
+			name:           "unsupported content type",
+			contentType:    "text/plain",
+			body:           `{"cep":"26140040"}`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   `{"message":"unsupported media type"}`,
+		},
+	}
+
+	validator, err := openapi.NewValidator(api.OpenAPISpec)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := new(MockWeatherUseCase)
+			if tt.mockWeather != nil || tt.mockErr != nil {
+				mockUseCase.On("GetWeatherByZipcode", mock.Anything, mock.Anything, mock.Anything).Return(tt.mockWeather, tt.mockErr)
+			}
+			router := NewWeatherHandler(mockUseCase, WithMiddleware(validator.Middleware)).SetupRoutes()
+
+			req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+			assert.NotContains(t, rec.Body.String(), "does not match OpenAPI spec")
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	weatherv1 "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/api/weather/v1"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/decode"
+	telemetry "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/domain"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/dto"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/usecase"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// WeatherGRPCServer implementa weatherv1.WeatherServiceServer sobre o mesmo use case do handler HTTP
+type WeatherGRPCServer struct {
+	weatherv1.UnimplementedWeatherServiceServer
+	weatherUseCase usecase.WeatherUseCase
+	decoder        *decode.Decoder
+}
+
+func NewWeatherGRPCServer(weatherUseCase usecase.WeatherUseCase) *WeatherGRPCServer {
+	return &WeatherGRPCServer{
+		weatherUseCase: weatherUseCase,
+		decoder:        decode.New(),
+	}
+}
+
+// NewGRPCServer cria o servidor gRPC instrumentado com otelgrpc, que extrai o
+// contexto de trace dos metadados para manter o trace conectado ao Service A.
+// O serviço grpc.health.v1 responde SERVING para o servidor e para
+// weather.v1.WeatherService (readiness do Service A com SERVICE_B_TRANSPORT=grpc).
+func NewGRPCServer(weatherServer *WeatherGRPCServer, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	server := grpc.NewServer(opts...)
+	weatherv1.RegisterWeatherServiceServer(server, weatherServer)
+
+	healthServer := grpchealth.NewServer()
+	healthServer.SetServingStatus(weatherv1.WeatherService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	return server
+}
+
+// GetWeather busca o clima pelo CEP
+func (s *WeatherGRPCServer) GetWeather(ctx context.Context, req *weatherv1.GetWeatherRequest) (*weatherv1.GetWeatherResponse, error) {
+	// Registra o baggage recebido do Serviço A no span da chamada
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(telemetry.BaggageAttributes(ctx)...)
+
+	// Com mTLS, registra a identidade do cliente já verificada no handshake
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.PeerCertificates) > 0 {
+			span.SetAttributes(semconv.TLSClientSubject(info.State.PeerCertificates[0].Subject.String()))
+		}
+	}
+
+	// As opções seguem as mesmas regras do body HTTP (dto.WeatherRequest)
+	weatherReq := dto.WeatherRequest{
+		CEP:              req.GetCep(),
+		Units:            req.GetUnits(),
+		ScientificKelvin: req.GetScientificKelvin(),
+	}
+	if req.Precision != nil {
+		precision := int(req.GetPrecision())
+		weatherReq.Precision = &precision
+	}
+	if err := s.decoder.Validate(&weatherReq); err != nil {
+		log.Printf("Error validating request: %v", err)
+		return nil, validationError(err)
+	}
+
+	weather, err := s.weatherUseCase.GetWeatherByZipcode(ctx, weatherReq.CEP, conversionOptions(weatherReq))
+	if err != nil {
+		log.Printf("Error processing request: %v", err)
+		return nil, grpcError(err)
+	}
+
+	return weatherResponse(weather), nil
+}
+
+// weatherResponse preenche apenas as escalas pedidas, como o JSON da API HTTP
+func weatherResponse(weather *domain.Weather) *weatherv1.GetWeatherResponse {
+	resp := &weatherv1.GetWeatherResponse{City: weather.City}
+	for _, unit := range weather.RequestedUnits() {
+		temp := weather.Temperature(unit)
+		switch unit {
+		case domain.Celsius:
+			resp.TempC = &temp
+		case domain.Fahrenheit:
+			resp.TempF = &temp
+		case domain.Kelvin:
+			resp.TempK = &temp
+		case domain.Rankine:
+			resp.TempR = &temp
+		}
+	}
+	return resp
+}
+
+// validationError converte campos inválidos em INVALID_ARGUMENT; falhas no CEP
+// mantêm a mensagem do contrato
+func validationError(err error) error {
+	var decodeErr *decode.Error
+	if !errors.As(err, &decodeErr) {
+		return status.Error(codes.Internal, "internal server error")
+	}
+	if hasField(decodeErr.Fields, "cep") {
+		return status.Error(codes.InvalidArgument, "invalid zipcode")
+	}
+
+	details := make([]string, 0, len(decodeErr.Fields))
+	for _, field := range decodeErr.Fields {
+		details = append(details, field.Field+" "+field.Message)
+	}
+	return status.Error(codes.InvalidArgument, decodeErr.Message+": "+strings.Join(details, "; "))
+}
+
+// grpcError converte erros de domínio em status gRPC, com as mesmas mensagens da API HTTP
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidZipcode):
+		return status.Error(codes.InvalidArgument, "invalid zipcode")
+	case errors.Is(err, domain.ErrZipcodeNotFound):
+		return status.Error(codes.NotFound, "can not find zipcode")
+	case errors.Is(err, domain.ErrWeatherNotFound):
+		return status.Error(codes.NotFound, "weather not found")
+	case errors.Is(err, domain.ErrInvalidLocation):
+		return status.Error(codes.FailedPrecondition, "invalid location")
+	case errors.Is(err, domain.ErrDeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
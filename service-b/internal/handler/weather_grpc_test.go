@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	weatherv1 "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/api/weather/v1"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWeatherGRPCServerGetWeather(t *testing.T) {
+	tests := []struct {
+		name             string
+		zipcode          string
+		request          *weatherv1.GetWeatherRequest
+		expectedOptions  *domain.Options
+		mockWeather      *domain.Weather
+		mockErr          error
+		expectedCode     codes.Code
+		expectedMessage  string
+		expectedResponse *weatherv1.GetWeatherResponse
+	}{
+		{
+			name:    "sucesso - CEP válido",
+			zipcode: "26140040",
+			mockWeather: &domain.Weather{
+				City:  "Belford Roxo",
+				TempC: 25.5,
+				TempF: 77.9,
+				TempK: 298.5,
+			},
+			expectedCode: codes.OK,
+			expectedResponse: &weatherv1.GetWeatherResponse{
+				City:  "Belford Roxo",
+				TempC: proto.Float64(25.5),
+				TempF: proto.Float64(77.9),
+				TempK: proto.Float64(298.5),
+			},
+		},
+		{
+			name:    "sucesso - escalas, precisão e Kelvin científico",
+			request: &weatherv1.GetWeatherRequest{Cep: "26140040", Units: []string{"R", "K"}, Precision: proto.Int32(2), ScientificKelvin: true},
+			expectedOptions: &domain.Options{
+				Units:            []domain.Unit{domain.Rankine, domain.Kelvin},
+				Precision:        2,
+				ScientificKelvin: true,
+			},
+			mockWeather: &domain.Weather{
+				City:  "Belford Roxo",
+				TempK: 298.65,
+				TempR: 537.57,
+				Units: []domain.Unit{domain.Rankine, domain.Kelvin},
+			},
+			expectedCode: codes.OK,
+			expectedResponse: &weatherv1.GetWeatherResponse{
+				City:  "Belford Roxo",
+				TempK: proto.Float64(298.65),
+				TempR: proto.Float64(537.57),
+			},
+		},
+		{
+			name:            "error - invalid zipcode",
+			zipcode:         "1234567a",
+			mockErr:         domain.ErrInvalidZipcode,
+			expectedCode:    codes.InvalidArgument,
+			expectedMessage: "invalid zipcode",
+		},
+		{
+			name:            "error - zipcode com tamanho inválido",
+			zipcode:         "123",
+			expectedCode:    codes.InvalidArgument,
+			expectedMessage: "invalid zipcode",
+		},
+		{
+			name:            "error - escala desconhecida",
+			request:         &weatherv1.GetWeatherRequest{Cep: "26140040", Units: []string{"X"}},
+			expectedCode:    codes.InvalidArgument,
+			expectedMessage: "validation failed: units[0] must be one of: C F K R",
+		},
+		{
+			name:            "error - precisão fora do limite",
+			request:         &weatherv1.GetWeatherRequest{Cep: "26140040", Precision: proto.Int32(7)},
+			expectedCode:    codes.InvalidArgument,
+			expectedMessage: "validation failed: precision must be at most 6",
+		},
+		{
+			name:            "error - zipcode not found",
+			zipcode:         "99999999",
+			mockErr:         domain.ErrZipcodeNotFound,
+			expectedCode:    codes.NotFound,
+			expectedMessage: "can not find zipcode",
+		},
+		{
+			name:            "error - weather not found",
+			zipcode:         "26140040",
+			mockErr:         domain.ErrWeatherNotFound,
+			expectedCode:    codes.NotFound,
+			expectedMessage: "weather not found",
+		},
+		{
+			name:            "error - invalid location",
+			zipcode:         "26140040",
+			mockErr:         domain.ErrInvalidLocation,
+			expectedCode:    codes.FailedPrecondition,
+			expectedMessage: "invalid location",
+		},
+		{
+			name:            "error - deadline exceeded",
+			zipcode:         "26140040",
+			mockErr:         fmt.Errorf("%w: %w", domain.ErrDeadlineExceeded, context.DeadlineExceeded),
+			expectedCode:    codes.DeadlineExceeded,
+			expectedMessage: "deadline exceeded",
+		},
+		{
+			name:            "error - unexpected",
+			zipcode:         "26140040",
+			mockErr:         errors.New("connection reset"),
+			expectedCode:    codes.Internal,
+			expectedMessage: "internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.request
+			if req == nil {
+				req = &weatherv1.GetWeatherRequest{Cep: tt.zipcode}
+			}
+			opts := domain.DefaultOptions()
+			if tt.expectedOptions != nil {
+				opts = *tt.expectedOptions
+			}
+			mockUseCase := new(MockWeatherUseCase)
+			if tt.mockWeather != nil || tt.mockErr != nil {
+				mockUseCase.On("GetWeatherByZipcode", mock.Anything, req.GetCep(), opts).Return(tt.mockWeather, tt.mockErr)
+			}
+
+			listener := bufconn.Listen(1024 * 1024)
+			server := NewGRPCServer(NewWeatherGRPCServer(mockUseCase))
+			go server.Serve(listener)
+			defer server.Stop()
+
+			conn, err := grpc.NewClient("passthrough:///bufnet",
+				grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+					return listener.DialContext(ctx)
+				}),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			)
+			require.NoError(t, err)
+			defer conn.Close()
+
+			resp, err := weatherv1.NewWeatherServiceClient(conn).GetWeather(context.Background(), req)
+
+			st := status.Convert(err)
+			assert.Equal(t, tt.expectedCode, st.Code())
+			if tt.expectedCode == codes.OK {
+				assert.True(t, proto.Equal(tt.expectedResponse, resp), "got %v", resp)
+			} else {
+				assert.Equal(t, tt.expectedMessage, st.Message())
+			}
+
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGRPCServerHealth(t *testing.T) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewGRPCServer(NewWeatherGRPCServer(new(MockWeatherUseCase)))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for _, service := range []string{"", "weather.v1.WeatherService"} {
+		resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err, service)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus(), service)
+	}
+}
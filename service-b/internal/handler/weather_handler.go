@@ -2,9 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/decode"
+	telemetry "github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/domain"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/dto"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/usecase"
@@ -12,19 +15,45 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type WeatherHandler struct {
 	weatherUseCase usecase.WeatherUseCase
 	tracer         trace.Tracer
+	decoder        *decode.Decoder
+	middlewares    chi.Middlewares
 }
 
-func NewWeatherHandler(weatherUseCase usecase.WeatherUseCase) *WeatherHandler {
-	return &WeatherHandler{
+// Option customiza o WeatherHandler
+type Option func(*WeatherHandler)
+
+// WithMiddleware adiciona middlewares executados dentro do span da requisição,
+// antes do handler, na ordem informada
+func WithMiddleware(middlewares ...func(http.Handler) http.Handler) Option {
+	return func(h *WeatherHandler) {
+		h.middlewares = append(h.middlewares, middlewares...)
+	}
+}
+
+// WithDecoder substitui o decoder padrão dos bodies JSON (limite de tamanho e campos desconhecidos)
+func WithDecoder(decoder *decode.Decoder) Option {
+	return func(h *WeatherHandler) {
+		h.decoder = decoder
+	}
+}
+
+func NewWeatherHandler(weatherUseCase usecase.WeatherUseCase, opts ...Option) *WeatherHandler {
+	h := &WeatherHandler{
 		weatherUseCase: weatherUseCase,
 		tracer:         otel.Tracer("service-b"),
+		decoder:        decode.New(),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 func (h *WeatherHandler) SetupRoutes() *chi.Mux {
@@ -35,8 +64,9 @@ func (h *WeatherHandler) SetupRoutes() *chi.Mux {
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
 
-	// Instrumentar com OpenTelemetry
-	r.Post("/weather", otelhttp.NewHandler(http.HandlerFunc(h.GetWeather), "service-b.process-weather").ServeHTTP)
+	// Instrumentar com OpenTelemetry; os middlewares do handler rodam dentro do span
+	weather := h.middlewares.Handler(http.HandlerFunc(h.GetWeather))
+	r.Post("/weather", otelhttp.NewHandler(weather, "service-b.process-weather").ServeHTTP)
 
 	return r
 }
@@ -45,15 +75,24 @@ func (h *WeatherHandler) SetupRoutes() *chi.Mux {
 func (h *WeatherHandler) GetWeather(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Parse do body
+	// Registra o baggage recebido do Serviço A no span da requisição
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(telemetry.BaggageAttributes(ctx)...)
+
+	// Com mTLS, registra a identidade do cliente já verificada no handshake
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		span.SetAttributes(semconv.TLSClientSubject(r.TLS.PeerCertificates[0].Subject.String()))
+	}
+
+	// Parse e validação do body
 	var req dto.WeatherRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "invalid request body")
+	if err := h.decoder.JSON(w, r, &req); err != nil {
+		h.handleDecodeError(w, err)
 		return
 	}
 
 	// Buscar clima
-	weather, err := h.weatherUseCase.GetWeatherByZipcode(ctx, req.CEP)
+	weather, err := h.weatherUseCase.GetWeatherByZipcode(ctx, req.CEP, conversionOptions(req))
 	if err != nil {
 		h.handleError(w, err)
 		return
@@ -67,20 +106,63 @@ func (h *WeatherHandler) GetWeather(w http.ResponseWriter, r *http.Request) {
 func (h *WeatherHandler) handleError(w http.ResponseWriter, err error) {
 	log.Printf("Error processing request: %v", err)
 
-	switch err {
-	case domain.ErrInvalidZipcode:
+	switch {
+	case errors.Is(err, domain.ErrInvalidZipcode):
 		h.writeErrorResponse(w, http.StatusUnprocessableEntity, "invalid zipcode")
-	case domain.ErrZipcodeNotFound:
+	case errors.Is(err, domain.ErrZipcodeNotFound):
 		h.writeErrorResponse(w, http.StatusNotFound, "can not find zipcode")
-	case domain.ErrWeatherNotFound:
+	case errors.Is(err, domain.ErrWeatherNotFound):
 		h.writeErrorResponse(w, http.StatusNotFound, "weather not found")
-	case domain.ErrInvalidLocation:
+	case errors.Is(err, domain.ErrInvalidLocation):
 		h.writeErrorResponse(w, http.StatusBadRequest, "invalid location")
+	case errors.Is(err, domain.ErrDeadlineExceeded):
+		h.writeErrorResponse(w, http.StatusGatewayTimeout, "deadline exceeded")
 	default:
 		h.writeErrorResponse(w, http.StatusInternalServerError, "internal server error")
 	}
 }
 
+// handleDecodeError responde com o status do erro de decodificação e os campos inválidos
+func (h *WeatherHandler) handleDecodeError(w http.ResponseWriter, err error) {
+	log.Printf("Error decoding request: %v", err)
+
+	var decodeErr *decode.Error
+	if !errors.As(err, &decodeErr) {
+		h.writeErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// Falhas no CEP mantêm a mensagem do contrato
+	message := decodeErr.Message
+	if decodeErr.Status == http.StatusUnprocessableEntity && hasField(decodeErr.Fields, "cep") {
+		message = "invalid zipcode"
+	}
+	h.writeJSONResponse(w, decodeErr.Status, dto.ErrorResponse{Message: message, Errors: decodeErr.Fields})
+}
+
+func hasField(fields []decode.FieldError, name string) bool {
+	for _, field := range fields {
+		if field.Field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// conversionOptions traduz as opções de escala e arredondamento da requisição;
+// campos ausentes mantêm o contrato original (C, F e K com uma casa decimal)
+func conversionOptions(req dto.WeatherRequest) domain.Options {
+	opts := domain.DefaultOptions()
+	for _, unit := range req.Units {
+		opts.Units = append(opts.Units, domain.Unit(unit))
+	}
+	if req.Precision != nil {
+		opts.Precision = *req.Precision
+	}
+	opts.ScientificKelvin = req.ScientificKelvin
+	return opts
+}
+
 func (h *WeatherHandler) writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -19,8 +20,8 @@ type MockWeatherUseCase struct {
 	mock.Mock
 }
 
-func (m *MockWeatherUseCase) GetWeatherByZipcode(ctx context.Context, zipcode string) (*domain.Weather, error) {
-	args := m.Called(ctx, zipcode)
+func (m *MockWeatherUseCase) GetWeatherByZipcode(ctx context.Context, zipcode string, opts domain.Options) (*domain.Weather, error) {
+	args := m.Called(ctx, zipcode, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -51,7 +52,7 @@ func TestWeatherHandlerGetWeather(t *testing.T) {
 		},
 		{
 			name:           "error - invalid zipcode",
-			zipcode:        "123",
+			zipcode:        "1234567a",
 			mockWeather:    nil,
 			mockErr:        domain.ErrInvalidZipcode,
 			expectedStatus: http.StatusUnprocessableEntity,
@@ -81,6 +82,14 @@ func TestWeatherHandlerGetWeather(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   `{"message":"invalid location"}`,
 		},
+		{
+			name:           "error - deadline exceeded",
+			zipcode:        "26140040",
+			mockWeather:    nil,
+			mockErr:        fmt.Errorf("%w: %w", domain.ErrDeadlineExceeded, context.DeadlineExceeded),
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   `{"message":"deadline exceeded"}`,
+		},
 		{
 			name:           "error - internal server error",
 			zipcode:        "26140040",
@@ -95,7 +104,7 @@ func TestWeatherHandlerGetWeather(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Criar mock
 			mockUseCase := new(MockWeatherUseCase)
-			mockUseCase.On("GetWeatherByZipcode", mock.Anything, tt.zipcode).Return(tt.mockWeather, tt.mockErr)
+			mockUseCase.On("GetWeatherByZipcode", mock.Anything, tt.zipcode, domain.DefaultOptions()).Return(tt.mockWeather, tt.mockErr)
 
 			// Criar handler
 			handler := NewWeatherHandler(mockUseCase)
@@ -123,3 +132,131 @@ func TestWeatherHandlerGetWeather(t *testing.T) {
 		})
 	}
 }
+
+func TestWeatherHandlerDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "malformed body",
+			contentType:    "application/json",
+			body:           `{"cep":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"message":"invalid request body"}`,
+		},
+		{
+			name:           "zipcode with wrong length",
+			contentType:    "application/json",
+			body:           `{"cep":"123"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"message":"invalid zipcode","errors":[{"field":"cep","message":"must have exactly 8 characters"}]}`,
+		},
+		{
+			name:           "unknown field",
+			contentType:    "application/json",
+			body:           `{"cep":"26140040","city":"Belford Roxo"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"message":"invalid request body","errors":[{"field":"city","message":"unknown field"}]}`,
+		},
+		{
+			name:           "trailing data",
+			contentType:    "application/json",
+			body:           `{"cep":"26140040"}{"cep":"01310100"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"message":"invalid request body"}`,
+		},
+		{
+			name:           "unsupported content type",
+			contentType:    "text/plain",
+			body:           `{"cep":"26140040"}`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   `{"message":"unsupported media type"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// O caso de uso não deve ser chamado quando o body é rejeitado
+			mockUseCase := new(MockWeatherUseCase)
+			router := NewWeatherHandler(mockUseCase).SetupRoutes()
+
+			req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+			mockUseCase.AssertNotCalled(t, "GetWeatherByZipcode", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWeatherHandlerConversionOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedOpts   *domain.Options
+		mockWeather    domain.Weather
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "units, precision and scientific kelvin",
+			body:           `{"cep":"26140040","units":["K","R"],"precision":2,"scientific_kelvin":true}`,
+			expectedOpts:   &domain.Options{Units: []domain.Unit{domain.Kelvin, domain.Rankine}, Precision: 2, ScientificKelvin: true},
+			mockWeather:    domain.NewWeatherWithOptions("Belford Roxo", 28.46, domain.Options{Units: []domain.Unit{domain.Kelvin, domain.Rankine}, Precision: 2, ScientificKelvin: true}),
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"city":"Belford Roxo","temp_K":301.61,"temp_R":542.9}`,
+		},
+		{
+			name:           "zero precision",
+			body:           `{"cep":"26140040","precision":0}`,
+			expectedOpts:   &domain.Options{Precision: 0},
+			mockWeather:    domain.NewWeatherWithOptions("Belford Roxo", 28.46, domain.Options{Precision: 0}),
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"city":"Belford Roxo","temp_C":28,"temp_F":83,"temp_K":301}`,
+		},
+		{
+			name:           "unknown unit",
+			body:           `{"cep":"26140040","units":["X"]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"message":"validation failed","errors":[{"field":"units[0]","message":"must be one of: C F K R"}]}`,
+		},
+		{
+			name:           "repeated unit",
+			body:           `{"cep":"26140040","units":["C","C"]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"message":"validation failed","errors":[{"field":"units","message":"must not contain duplicates"}]}`,
+		},
+		{
+			name:           "precision out of range",
+			body:           `{"cep":"26140040","precision":7}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"message":"validation failed","errors":[{"field":"precision","message":"must be at most 6"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := new(MockWeatherUseCase)
+			if tt.expectedOpts != nil {
+				mockUseCase.On("GetWeatherByZipcode", mock.Anything, "26140040", *tt.expectedOpts).Return(&tt.mockWeather, nil)
+			}
+			router := NewWeatherHandler(mockUseCase).SetupRoutes()
+
+			req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.JSONEq(t, tt.expectedBody, recorder.Body.String())
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
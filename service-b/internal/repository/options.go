@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// ClientOption customiza os clientes HTTP dos provedores (ViaCEP e WeatherAPI)
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	timeout  time.Duration
+	wrappers []func(http.RoundTripper) http.RoundTripper
+}
+
+// DefaultTimeout limita cada chamada a um provedor; o orçamento da requisição
+// (X-Request-Timeout) pode encerrá-la antes
+const DefaultTimeout = 10 * time.Second
+
+// WithTimeout substitui DefaultTimeout
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.timeout = d
+	}
+}
+
+// WithTransportWrapper envolve o transport antes da instrumentação (ex: replay.Recorder.Wrap)
+func WithTransportWrapper(wrap func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *clientConfig) {
+		c.wrappers = append(c.wrappers, wrap)
+	}
+}
+
+func newHTTPClient(opts ...ClientOption) *http.Client {
+	config := &clientConfig{timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	transport := http.DefaultTransport
+	for _, wrap := range config.wrappers {
+		transport = wrap(transport)
+	}
+
+	return &http.Client{
+		// Propaga apenas o trace context: o baggage não deve vazar para provedores externos
+		Transport: otelhttp.NewTransport(transport, otelhttp.WithPropagators(propagation.TraceContext{})),
+		Timeout:   config.timeout,
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"flag"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/replay"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test ./internal/repository -run Replay -record regrava os golden files
+// contra os provedores de VIACEP_BASE_URL, WEATHER_API_BASE_URL e WEATHER_API_KEY
+var record = flag.Bool("record", false, "record golden files against the real providers")
+
+// replayTarget devolve a base URL e o Recorder do golden file testdata/replay/<name>.json
+func replayTarget(t *testing.T, name, envURL, defaultURL string) (string, *replay.Recorder) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "replay", name+".json")
+	if !*record {
+		recorder, err := replay.New(replay.ModeReplay, path)
+		require.NoError(t, err)
+		// O host não importa na reprodução: nenhuma conexão é aberta
+		target, err := url.Parse(defaultURL)
+		require.NoError(t, err)
+		target.Scheme, target.Host = "http", "replay.invalid"
+		return target.String(), recorder
+	}
+
+	recorder, err := replay.New(replay.ModeRecord, path)
+	require.NoError(t, err)
+	if baseURL := os.Getenv(envURL); baseURL != "" {
+		return baseURL, recorder
+	}
+	return defaultURL, recorder
+}
+
+func TestViaCEPClientReplay(t *testing.T) {
+	baseURL, recorder := replayTarget(t, "viacep", "VIACEP_BASE_URL", "https://viacep.com.br/ws")
+	client := NewViaCEPClient(baseURL, WithTransportWrapper(recorder.Wrap))
+
+	tests := []struct {
+		zipcode     string
+		expected    *domain.Location
+		expectedErr error
+	}{
+		{zipcode: "26140040", expected: &domain.Location{City: "Belford Roxo", State: "RJ"}},
+		{zipcode: "01310100", expected: &domain.Location{City: "São Paulo", State: "SP"}},
+		{zipcode: "99999999", expectedErr: domain.ErrZipcodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zipcode, func(t *testing.T) {
+			location, err := client.GetLocationByZipcode(context.Background(), tt.zipcode)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, location)
+		})
+	}
+	if !*record {
+		assert.Empty(t, recorder.Unused(), "golden file has interactions no test replays")
+	}
+}
+
+func TestWeatherClientReplay(t *testing.T) {
+	baseURL, recorder := replayTarget(t, "weatherapi", "WEATHER_API_BASE_URL", "https://api.weatherapi.com/v1")
+	apiKey := os.Getenv("WEATHER_API_KEY")
+	if !*record {
+		// A chave gravada foi removida; qualquer valor reproduz as trocas
+		apiKey = "replay"
+	}
+	client := NewWeatherClient(baseURL, apiKey, WithTransportWrapper(recorder.Wrap))
+
+	tests := []struct {
+		location    *domain.Location
+		expectedErr error
+	}{
+		{location: &domain.Location{City: "Belford Roxo", State: "RJ"}},
+		{location: &domain.Location{City: "São Paulo", State: "SP"}},
+		{location: &domain.Location{City: "Atlantis", State: "XX"}, expectedErr: domain.ErrWeatherNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location.City, func(t *testing.T) {
+			temp, err := client.GetTemperatureByLocation(context.Background(), tt.location)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+			// Temperaturas reais variam entre gravações; o contrato é a faixa plausível
+			assert.Greater(t, temp, -50.0)
+			assert.Less(t, temp, 60.0)
+		})
+	}
+	if !*record {
+		assert.Empty(t, recorder.Unused(), "golden file has interactions no test replays")
+	}
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/stubs"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Garante que os stubs do modo offline seguem o contrato esperado pelos clientes reais
+func TestClientsAgainstStubs(t *testing.T) {
+	fixtures, err := stubs.LoadFixturesFile("")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(stubs.New(fixtures, stubs.WithAPIKey("stub")).Handler())
+	defer server.Close()
+
+	viacep := NewViaCEPClient(server.URL + "/ws")
+	weather := NewWeatherClient(server.URL+"/v1", "stub")
+
+	location, err := viacep.GetLocationByZipcode(context.Background(), "26140040")
+	require.NoError(t, err)
+	assert.Equal(t, "Belford Roxo", location.City)
+	assert.Equal(t, "RJ", location.State)
+
+	temp, err := weather.GetTemperatureByLocation(context.Background(), location)
+	require.NoError(t, err)
+	assert.Equal(t, 31.2, temp)
+
+	_, err = viacep.GetLocationByZipcode(context.Background(), "99999999")
+	assert.ErrorIs(t, err, domain.ErrZipcodeNotFound)
+
+	_, err = weather.GetTemperatureByLocation(context.Background(), &domain.Location{City: "Atlantis", State: "XX"})
+	assert.ErrorIs(t, err, domain.ErrWeatherNotFound)
+
+	_, err = NewWeatherClient(server.URL+"/v1", "wrong").GetTemperatureByLocation(context.Background(), location)
+	assert.Error(t, err)
+}
@@ -6,12 +6,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"time"
 
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/domain"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/dto"
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -25,14 +24,11 @@ type viacepClient struct {
 	tracer     trace.Tracer
 }
 
-func NewViaCEPClient(baseURL string) ViaCEPClient {
+func NewViaCEPClient(baseURL string, opts ...ClientOption) ViaCEPClient {
 	return &viacepClient{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Transport: otelhttp.NewTransport(http.DefaultTransport),
-			Timeout:   10 * time.Second,
-		},
-		tracer: otel.Tracer("service-b"),
+		baseURL:    baseURL,
+		httpClient: newHTTPClient(opts...),
+		tracer:     otel.Tracer("service-b"),
 	}
 }
 
@@ -43,6 +39,7 @@ func (c *viacepClient) GetLocationByZipcode(ctx context.Context, zipcode string)
 
 	if err := domain.ValidateZipcode(zipcode); err != nil {
 		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
 
@@ -53,12 +50,14 @@ func (c *viacepClient) GetLocationByZipcode(ctx context.Context, zipcode string)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("error querying ViaCEP: %w", err)
 	}
 	defer resp.Body.Close()
@@ -66,17 +65,20 @@ func (c *viacepClient) GetLocationByZipcode(ctx context.Context, zipcode string)
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("error reading ViaCEP response: %w", err)
 	}
 
 	var viacepResp dto.ViaCEPResponse
 	if err := json.Unmarshal(body, &viacepResp); err != nil {
 		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("error parsing ViaCEP response: %w", err)
 	}
 
 	if viacepResp.Erro == "true" || viacepResp.Localidade == "" {
 		span.RecordError(domain.ErrZipcodeNotFound)
+		span.SetStatus(codes.Error, domain.ErrZipcodeNotFound.Error())
 		return nil, domain.ErrZipcodeNotFound
 	}
 
@@ -7,12 +7,11 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"time"
 
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/domain"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/dto"
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -27,15 +26,12 @@ type weatherClient struct {
 	tracer     trace.Tracer
 }
 
-func NewWeatherClient(baseURL, apiKey string) WeatherClient {
+func NewWeatherClient(baseURL, apiKey string, opts ...ClientOption) WeatherClient {
 	return &weatherClient{
-		baseURL: baseURL,
-		apiKey:  apiKey,
-		httpClient: &http.Client{
-			Transport: otelhttp.NewTransport(http.DefaultTransport),
-			Timeout:   10 * time.Second,
-		},
-		tracer: otel.Tracer("service-b"),
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+		httpClient: newHTTPClient(opts...),
+		tracer:     otel.Tracer("service-b"),
 	}
 }
 
@@ -47,6 +43,7 @@ func (c *weatherClient) GetTemperatureByLocation(ctx context.Context, location *
 
 	if location == nil || location.City == "" {
 		span.RecordError(domain.ErrInvalidLocation)
+		span.SetStatus(codes.Error, domain.ErrInvalidLocation.Error())
 		return 0, domain.ErrInvalidLocation
 	}
 
@@ -57,39 +54,47 @@ func (c *weatherClient) GetTemperatureByLocation(ctx context.Context, location *
 	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 	if err != nil {
 		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return 0, fmt.Errorf("error creating request: %w", err)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return 0, fmt.Errorf("error querying WeatherAPI: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusUnauthorized {
-		span.RecordError(fmt.Errorf("invalid API key"))
-		return 0, fmt.Errorf("invalid API key")
+		err := fmt.Errorf("invalid API key")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return 0, err
 	}
 	if resp.StatusCode == http.StatusBadRequest {
 		span.RecordError(domain.ErrWeatherNotFound)
+		span.SetStatus(codes.Error, domain.ErrWeatherNotFound.Error())
 		return 0, domain.ErrWeatherNotFound
 	}
 	if resp.StatusCode != http.StatusOK {
 		err := fmt.Errorf("error in WeatherAPI: status %d", resp.StatusCode)
 		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return 0, err
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return 0, fmt.Errorf("error reading WeatherAPI response: %w", err)
 	}
 
 	var weatherResp dto.WeatherAPIResponse
 	if err := json.Unmarshal(body, &weatherResp); err != nil {
 		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return 0, fmt.Errorf("error parsing WeatherAPI response: %w", err)
 	}
 
@@ -2,42 +2,78 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/deadline"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/domain"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/repository"
 )
 
 type WeatherUseCase interface {
-	GetWeatherByZipcode(ctx context.Context, zipcode string) (*domain.Weather, error)
+	GetWeatherByZipcode(ctx context.Context, zipcode string, opts domain.Options) (*domain.Weather, error)
 }
 
 type weatherUseCase struct {
 	viacepClient  repository.ViaCEPClient
 	weatherClient repository.WeatherClient
+	zipcodeShare  float64
 }
 
-func NewWeatherUseCase(viacepClient repository.ViaCEPClient, weatherClient repository.WeatherClient) WeatherUseCase {
-	return &weatherUseCase{
+// DefaultZipcodeBudgetShare é a fração do prazo restante reservada ao ViaCEP
+const DefaultZipcodeBudgetShare = 0.5
+
+// Option customiza o WeatherUseCase
+type Option func(*weatherUseCase)
+
+// WithZipcodeBudgetShare define a fração (0 a 1) do prazo restante da
+// requisição reservada à busca do CEP; a temperatura fica com o que sobrar
+func WithZipcodeBudgetShare(share float64) Option {
+	return func(u *weatherUseCase) {
+		u.zipcodeShare = share
+	}
+}
+
+func NewWeatherUseCase(viacepClient repository.ViaCEPClient, weatherClient repository.WeatherClient, opts ...Option) WeatherUseCase {
+	u := &weatherUseCase{
 		viacepClient:  viacepClient,
 		weatherClient: weatherClient,
+		zipcodeShare:  DefaultZipcodeBudgetShare,
 	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
 }
 
-func (u *weatherUseCase) GetWeatherByZipcode(ctx context.Context, zipcode string) (*domain.Weather, error) {
-	// 1. Buscar localização pelo CEP
-	location, err := u.viacepClient.GetLocationByZipcode(ctx, zipcode)
+func (u *weatherUseCase) GetWeatherByZipcode(ctx context.Context, zipcode string, opts domain.Options) (*domain.Weather, error) {
+	// 1. Buscar localização pelo CEP, com parte do prazo da requisição
+	zipcodeCtx, cancel := deadline.Share(ctx, u.zipcodeShare)
+	location, err := u.viacepClient.GetLocationByZipcode(zipcodeCtx, zipcode)
+	cancel()
 	if err != nil {
-		return nil, err
+		return nil, timeoutError(err)
 	}
 
-	// 2. Buscar temperatura pela localização
+	// 2. Buscar temperatura pela localização, com o restante do prazo
 	tempCelsius, err := u.weatherClient.GetTemperatureByLocation(ctx, location)
 	if err != nil {
-		return nil, err
+		return nil, timeoutError(err)
 	}
 
-	// 3. Criar objeto Weather com conversões e cidade
-	weather := domain.NewWeather(location.City, tempCelsius)
+	// 3. Criar objeto Weather com as conversões pedidas e cidade
+	weather := domain.NewWeatherWithOptions(location.City, tempCelsius, opts)
 
 	return &weather, nil
 }
+
+// timeoutError marca com domain.ErrDeadlineExceeded as falhas por prazo
+// esgotado, seja o da requisição ou o timeout do cliente do provedor
+func timeoutError(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", domain.ErrDeadlineExceeded, err)
+	}
+	return err
+}
@@ -2,8 +2,11 @@ package usecase
 
 import (
 	"context"
+	"net/url"
 	"testing"
+	"time"
 
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/deadline"
 	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/domain"
 
 	"github.com/stretchr/testify/assert"
@@ -124,7 +127,7 @@ func TestWeatherUseCaseGetWeatherByZipcode(t *testing.T) {
 			usecase := NewWeatherUseCase(mockViaCEP, mockWeather)
 
 			// Executar teste
-			result, err := usecase.GetWeatherByZipcode(context.Background(), tt.zipcode)
+			result, err := usecase.GetWeatherByZipcode(context.Background(), tt.zipcode, domain.DefaultOptions())
 
 			// Verificar resultado
 			if tt.expectedErr != nil {
@@ -144,3 +147,55 @@ func TestWeatherUseCaseGetWeatherByZipcode(t *testing.T) {
 		})
 	}
 }
+
+func TestWeatherUseCaseSplitsDeadline(t *testing.T) {
+	location := &domain.Location{City: "Belford Roxo", State: "RJ"}
+	var zipcodeBudget, weatherBudget time.Duration
+
+	mockViaCEP := new(MockViaCEPClient)
+	mockViaCEP.On("GetLocationByZipcode", mock.Anything, "26140040").Return(location, nil).Run(func(args mock.Arguments) {
+		zipcodeBudget, _ = deadline.Remaining(args.Get(0).(context.Context))
+	})
+	mockWeather := new(MockWeatherClient)
+	mockWeather.On("GetTemperatureByLocation", mock.Anything, location).Return(25.0, nil).Run(func(args mock.Arguments) {
+		weatherBudget, _ = deadline.Remaining(args.Get(0).(context.Context))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+	defer cancel()
+
+	_, err := NewWeatherUseCase(mockViaCEP, mockWeather, WithZipcodeBudgetShare(0.25)).GetWeatherByZipcode(ctx, "26140040", domain.DefaultOptions())
+	assert.NoError(t, err)
+	assert.InDelta(t, time.Second, zipcodeBudget, float64(100*time.Millisecond))
+	assert.InDelta(t, 4*time.Second, weatherBudget, float64(100*time.Millisecond), "weather gets what is left")
+}
+
+func TestWeatherUseCaseDeadlineExceeded(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		expectedErr error
+	}{
+		{name: "request budget", err: &url.Error{Op: "Get", URL: "http://viacep", Err: context.DeadlineExceeded}, expectedErr: domain.ErrDeadlineExceeded},
+		{name: "client timeout", err: &url.Error{Op: "Get", URL: "http://viacep", Err: timeoutErr{}}, expectedErr: domain.ErrDeadlineExceeded},
+		{name: "other errors are kept", err: domain.ErrZipcodeNotFound, expectedErr: domain.ErrZipcodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockViaCEP := new(MockViaCEPClient)
+			mockViaCEP.On("GetLocationByZipcode", mock.Anything, "26140040").Return(nil, tt.err)
+
+			_, err := NewWeatherUseCase(mockViaCEP, new(MockWeatherClient)).GetWeatherByZipcode(context.Background(), "26140040", domain.DefaultOptions())
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.ErrorIs(t, err, tt.err, "the original error is kept")
+		})
+	}
+}
+
+// timeoutErr simula o erro do http.Client quando Client.Timeout estoura
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "Client.Timeout exceeded" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
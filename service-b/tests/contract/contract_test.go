@@ -0,0 +1,65 @@
+package contract
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/contract"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/stubs"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// providerStates prepara os provedores emulados para cada estado citado pelos consumidores
+var providerStates = map[string][]stubs.Option{
+	"":                                    nil,
+	"zipcode 26140040 is in Belford Roxo": nil,
+	"zipcode 99999999 does not exist":     {stubs.WithUnknownZipcode(stubs.UnknownNotFound)},
+	"weather provider is failing":         {stubs.WithWeatherFaults(stubs.Faults{ErrorRate: 1, ErrorStatus: http.StatusServiceUnavailable})},
+}
+
+// newProvider monta o Service B como em produção (app.New a partir de
+// app.NewConfig) contra ViaCEP e WeatherAPI emulados, validando as respostas
+// contra api/openapi.json
+func newProvider(t *testing.T, opts []stubs.Option) http.Handler {
+	t.Helper()
+
+	fixtures, err := stubs.LoadFixturesFile("")
+	require.NoError(t, err)
+	upstream := httptest.NewServer(stubs.New(fixtures, opts...).Handler())
+	t.Cleanup(upstream.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	config := app.NewConfig()
+	config.Set("viacep_base_url", upstream.URL+"/ws")
+	config.Set("weather_api_base_url", upstream.URL+"/v1")
+	config.Set("weather_api_key", "contract")
+	config.Set("openapi_validate_responses", true)
+	cfg, err := app.LoadConfig(config)
+	require.NoError(t, err)
+
+	return app.New(ctx, cfg).Handler
+}
+
+// TestServiceAContract verifica o Service B contra as interações publicadas pelo Service A
+func TestServiceAContract(t *testing.T) {
+	published, err := contract.Load(filepath.Join("..", "..", "..", "contracts", "service-a-service-b.json"))
+	require.NoError(t, err)
+	require.Equal(t, "service-b", published.Provider)
+	require.NotEmpty(t, published.Interactions)
+
+	for _, interaction := range published.Interactions {
+		t.Run(interaction.Description, func(t *testing.T) {
+			opts, ok := providerStates[interaction.ProviderState]
+			require.True(t, ok, "unknown provider state %q", interaction.ProviderState)
+
+			assert.NoError(t, contract.Verify(newProvider(t, opts), interaction))
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/pkg/otel/oteltest"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/handler"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/repository"
+	"github.com/ElizCarvalho/fc-pos-golang-lab-weather-api-com-otel/service-b/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestWeatherAPITraceStructure(t *testing.T) {
+	unset, errored := codes.Unset.String(), codes.Error.String()
+
+	tests := []struct {
+		name           string
+		body           string
+		viacepBody     string
+		weatherStatus  int
+		weatherBody    string
+		expectedStatus int
+		expectedSpans  []string
+	}{
+		{
+			name:           "success",
+			body:           `{"cep":"26140040"}`,
+			viacepBody:     `{"localidade":"Belford Roxo","uf":"RJ"}`,
+			weatherStatus:  http.StatusOK,
+			weatherBody:    `{"current":{"temp_c":25.5}}`,
+			expectedStatus: http.StatusOK,
+			expectedSpans: []string{
+				"service-b.process-weather <- root : " + unset,
+				"service-b.fetch-zipcode <- service-b.process-weather : " + unset,
+				"HTTP GET <- service-b.fetch-zipcode : " + unset,
+				"service-b.fetch-weather <- service-b.process-weather : " + unset,
+				"HTTP GET <- service-b.fetch-weather : " + unset,
+			},
+		},
+		{
+			name:           "error - invalid request body",
+			body:           `{"cep":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedSpans: []string{
+				"service-b.process-weather <- root : " + unset,
+			},
+		},
+		{
+			name:           "error - invalid zipcode",
+			body:           `{"cep":"1234567a"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedSpans: []string{
+				"service-b.process-weather <- root : " + unset,
+				"service-b.fetch-zipcode <- service-b.process-weather : " + errored,
+			},
+		},
+		{
+			name:           "error - zipcode not found",
+			body:           `{"cep":"99999999"}`,
+			viacepBody:     `{"erro":"true"}`,
+			expectedStatus: http.StatusNotFound,
+			expectedSpans: []string{
+				"service-b.process-weather <- root : " + unset,
+				"service-b.fetch-zipcode <- service-b.process-weather : " + errored,
+				"HTTP GET <- service-b.fetch-zipcode : " + unset,
+			},
+		},
+		{
+			name:           "error - weather not found",
+			body:           `{"cep":"26140040"}`,
+			viacepBody:     `{"localidade":"Belford Roxo","uf":"RJ"}`,
+			weatherStatus:  http.StatusBadRequest,
+			expectedStatus: http.StatusNotFound,
+			expectedSpans: []string{
+				"service-b.process-weather <- root : " + unset,
+				"service-b.fetch-zipcode <- service-b.process-weather : " + unset,
+				"HTTP GET <- service-b.fetch-zipcode : " + unset,
+				"service-b.fetch-weather <- service-b.process-weather : " + errored,
+				"HTTP GET <- service-b.fetch-weather : " + errored,
+			},
+		},
+		{
+			name:           "error - weather provider failure",
+			body:           `{"cep":"26140040"}`,
+			viacepBody:     `{"localidade":"Belford Roxo","uf":"RJ"}`,
+			weatherStatus:  http.StatusServiceUnavailable,
+			expectedStatus: http.StatusInternalServerError,
+			expectedSpans: []string{
+				"service-b.process-weather <- root : " + errored,
+				"service-b.fetch-zipcode <- service-b.process-weather : " + unset,
+				"HTTP GET <- service-b.fetch-zipcode : " + unset,
+				"service-b.fetch-weather <- service-b.process-weather : " + errored,
+				"HTTP GET <- service-b.fetch-weather : " + errored,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Registrar um recorder de spans em memória
+			recorder := oteltest.Record(t)
+
+			// Criar servidores mock do ViaCEP e da WeatherAPI
+			viacep := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.viacepBody))
+			}))
+			defer viacep.Close()
+
+			weatherAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.weatherStatus)
+				w.Write([]byte(tt.weatherBody))
+			}))
+			defer weatherAPI.Close()
+
+			// Criar dependências reais apontando para os mocks
+			weatherUseCase := usecase.NewWeatherUseCase(
+				repository.NewViaCEPClient(viacep.URL),
+				repository.NewWeatherClient(weatherAPI.URL, "test-key"),
+			)
+			router := handler.NewWeatherHandler(weatherUseCase).SetupRoutes()
+
+			// Executar requisição
+			req := httptest.NewRequest("POST", "/weather", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			// Verificar resposta
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			// Verificar árvore de spans
+			spans := recorder.Ended()
+			assert.ElementsMatch(t, tt.expectedSpans, oteltest.SpanTree(spans))
+			assert.Len(t, recorder.Started(), len(spans), "every started span must be ended")
+			for _, s := range spans {
+				assert.Equal(t, spans[0].SpanContext().TraceID(), s.SpanContext().TraceID())
+			}
+		})
+	}
+}
@@ -71,7 +71,7 @@ func TestWeatherAPIIntegration(t *testing.T) {
 		},
 		{
 			name:               "error - invalid zipcode",
-			zipcode:            "123",
+			zipcode:            "1234567a",
 			mockLocation:       nil,
 			mockLocationErr:    domain.ErrInvalidZipcode,
 			mockTemperature:    0,